@@ -11,7 +11,10 @@ import (
 	_ "net/http/pprof"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
+	"unicode"
 )
 
 func main() {
@@ -19,12 +22,12 @@ func main() {
 	slog.SetDefault(logger)
 
 	if err := godotenv.Load(); err != nil {
-		slog.Error("Error loading .env file: %v", err)
+		slog.Error("Error loading .env file", "error", err)
 		os.Exit(1)
 	}
 
 	if err := initConfig(); err != nil {
-		slog.Error("init config err: %v", err)
+		slog.Error("init config err", "error", err)
 		os.Exit(1)
 	}
 
@@ -33,6 +36,14 @@ func main() {
 		os.Exit(1)
 	}
 
+	applyImageConfig()
+
+	if err := bot.InitDiskCache(viper.GetString("image.disk_cache.dir"),
+		int64(viper.GetSizeInBytes("image.disk_cache.max_size"))); err != nil {
+		slog.Error("Failed to init disk image cache", "error", err)
+		os.Exit(1)
+	}
+
 	ctx, cacheCancel := context.WithCancel(context.Background())
 	defer cacheCancel()
 	go bot.ClearImageCachePeriodically(ctx, viper.GetDuration("image.cache.ttl"))
@@ -59,8 +70,12 @@ func main() {
 		os.Exit(1)
 	}
 
-	kinopoiskAPI := api.NewKinopoiskAPI(viper.GetString("APIKey"))
-	tgBot, err := bot.NewBot(viper.GetString("TelegramToken"), redisClient, kinopoiskAPI)
+	apiCacheTTL := viper.GetDuration("api.cache.ttl")
+	kinopoiskAPI := api.NewKinopoiskAPI(viper.GetString("APIKey"), apiCacheTTL)
+	if apiCacheTTL > 0 {
+		go kinopoiskAPI.ClearExpiredResponsesPeriodically(ctx, apiCacheTTL)
+	}
+	tgBot, err := bot.NewBot(viper.GetString("TelegramToken"), redisClient, kinopoiskAPI, botConfig(), reloadConfig)
 	if err != nil {
 		slog.Error("failed to create bot", slog.String("error", err.Error()))
 		os.Exit(1)
@@ -90,5 +105,47 @@ func initConfig() error {
 	if apiErr != nil {
 		slog.Error("failed to bind kinopoisk api key", "error", apiErr)
 	}
+	adminsErr := viper.BindEnv("admins", "ADMIN_IDS")
+	if adminsErr != nil {
+		slog.Error("failed to bind admin ids", "error", adminsErr)
+	}
 	return viper.ReadInConfig()
 }
+
+func applyImageConfig() {
+	bot.SetPosterHosts(viper.GetStringSlice("image.poster.hosts"))
+	bot.SetPosterProcessing(viper.GetInt("image.poster.max_size"), viper.GetInt("image.poster.quality"))
+}
+
+// botConfig reads the runtime options. Admins come from the config file as a
+// list or from ADMIN_IDS as IDs separated by commas or spaces.
+func botConfig() bot.Config {
+	var admins []int64
+	for _, entry := range viper.GetStringSlice("admins") {
+		ids := strings.FieldsFunc(entry, func(r rune) bool {
+			return r == ',' || unicode.IsSpace(r)
+		})
+		for _, id := range ids {
+			adminID, err := strconv.ParseInt(id, 10, 64)
+			if err != nil {
+				slog.Warn("Invalid admin ID in config", "id", id)
+				continue
+			}
+			admins = append(admins, adminID)
+		}
+	}
+	return bot.Config{
+		Admins:        admins,
+		APIDailyQuota: viper.GetInt("api.daily_quota"),
+	}
+}
+
+// reloadConfig rereads the config file for /reload. Redis and the tokens
+// aren't reconnected, only the options that can change at runtime are applied.
+func reloadConfig() (bot.Config, error) {
+	if err := viper.ReadInConfig(); err != nil {
+		return bot.Config{}, err
+	}
+	applyImageConfig()
+	return botConfig(), nil
+}
@@ -1,126 +1,299 @@
 package api
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"golang.org/x/sync/singleflight"
 	"io"
 	"kinopoisk-bot/internal/model"
 	"log/slog"
 	"net/http"
 	"net/url"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 )
 
 type KinopoiskAPI struct {
 	apiKey  string
 	baseUrl string
+	// requests coalesces concurrent identical GET requests
+	requests singleflight.Group
+	// responses keeps recent response bodies for cacheTTL, so prefetched
+	// pages are served without another request
+	responses *responseCache
+	cacheTTL  time.Duration
+
+	usageMu       sync.Mutex
+	usageDay      string
+	requestsToday int
 }
 
-func NewKinopoiskAPI(apiKey string) *KinopoiskAPI {
+// NewKinopoiskAPI creates an API client. Responses are cached for cacheTTL,
+// a zero cacheTTL disables the cache.
+func NewKinopoiskAPI(apiKey string, cacheTTL time.Duration) *KinopoiskAPI {
 	return &KinopoiskAPI{
-		apiKey:  apiKey,
-		baseUrl: "https://api.kinopoisk.dev",
+		apiKey:    apiKey,
+		baseUrl:   "https://api.kinopoisk.dev",
+		responses: newResponseCache(responseCacheMaxEntries),
+		cacheTTL:  cacheTTL,
+	}
+}
+
+// ClearResponseCache drops all cached responses and returns how many there were.
+func (k *KinopoiskAPI) ClearResponseCache() int {
+	return k.responses.Clear()
+}
+
+// ResponseCacheSize returns the number of cached responses.
+func (k *KinopoiskAPI) ResponseCacheSize() int {
+	return k.responses.Len()
+}
+
+// RequestsToday returns the number of requests sent to the API since
+// midnight UTC, which is what the daily quota is counted against.
+func (k *KinopoiskAPI) RequestsToday() int {
+	k.usageMu.Lock()
+	defer k.usageMu.Unlock()
+	if k.usageDay != time.Now().UTC().Format(time.DateOnly) {
+		return 0
+	}
+	return k.requestsToday
+}
+
+func (k *KinopoiskAPI) countRequest() {
+	k.usageMu.Lock()
+	defer k.usageMu.Unlock()
+	day := time.Now().UTC().Format(time.DateOnly)
+	if k.usageDay != day {
+		k.usageDay = day
+		k.requestsToday = 0
+	}
+	k.requestsToday++
+}
+
+// ClearExpiredResponsesPeriodically drops expired responses every interval.
+func (k *KinopoiskAPI) ClearExpiredResponsesPeriodically(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			k.responses.ClearExpired()
+		case <-ctx.Done():
+			return
+		}
 	}
 }
 
-func (k *KinopoiskAPI) doRequest(url string, result interface{}) error {
-	req, err := http.NewRequest("GET", url, nil)
+// doRequest fetches url and decodes the JSON response into result.
+// Concurrent calls for the same url share one HTTP request.
+func (k *KinopoiskAPI) doRequest(ctx context.Context, url string, result interface{}) error {
+	if body, ok := k.responses.Get(url); ok {
+		return json.Unmarshal(body, result)
+	}
+
+	request := func() (interface{}, error) {
+		body, err := k.fetch(ctx, url)
+		if err == nil && k.cacheTTL > 0 {
+			k.responses.Put(url, body, k.cacheTTL)
+		}
+		return body, err
+	}
+	body, err, shared := k.requests.Do(url, request)
+	// Общий запрос мог отменить другой вызывающий, например фоновая подгрузка страницы
+	if err != nil && shared && errors.Is(err, context.Canceled) && ctx.Err() == nil {
+		body, err, shared = k.requests.Do(url, request)
+	}
 	if err != nil {
 		return err
 	}
+	if shared {
+		slog.Debug("Shared API response", "url", url)
+	}
+	return json.Unmarshal(body.([]byte), result)
+}
+
+// doUnsharedRequest is doRequest without coalescing, for endpoints that
+// return a different result on every call.
+func (k *KinopoiskAPI) doUnsharedRequest(url string, result interface{}) error {
+	body, err := k.fetch(context.Background(), url)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(body, result)
+}
+
+func (k *KinopoiskAPI) fetch(ctx context.Context, url string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return nil, err
+	}
 	req.Header.Add("accept", "application/json")
 	req.Header.Add("X-API-KEY", k.apiKey)
+	k.countRequest()
 
 	client := &http.Client{}
 	resp, err := client.Do(req)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("bad status: %d", resp.StatusCode)
+		return nil, fmt.Errorf("bad status: %d", resp.StatusCode)
 	}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return err
-	}
-
-	return json.Unmarshal(body, result)
+	return io.ReadAll(resp.Body)
 }
 
-func (k *KinopoiskAPI) SearchMovie(query string, page int) ([]model.Movie, error) {
+// SearchMovie returns a page of full-text search results. The request is
+// abandoned once ctx is done.
+func (k *KinopoiskAPI) SearchMovie(ctx context.Context, query string, page int, limit int) ([]model.Movie, error) {
 	slog.Debug("Started SearchMovie")
-	searchUrl := fmt.Sprintf("%s/v1.4/movie/search?page=%d&limit=10&query=%s",
-		k.baseUrl, page, url.QueryEscape(query))
+	searchUrl := fmt.Sprintf("%s/v1.4/movie/search?page=%d&limit=%d&query=%s",
+		k.baseUrl, page, limit, url.QueryEscape(query))
 
 	var data MovieResponse
-	if err := k.doRequest(searchUrl, &data); err != nil {
-		slog.Error("SearchMovie fetch err:", err)
+	if err := k.doRequest(ctx, searchUrl, &data); err != nil {
+		slog.Error("SearchMovie fetch err:", "error", err)
 		return nil, err
 	}
 
 	var movies []model.Movie
 	for _, doc := range data.Docs {
-		if doc.Name == "" {
+		movie := doc.toMovie()
+		if movie.Title == "" {
 			continue
 		}
-		movies = append(movies, model.Movie{
-			Id:          doc.Id,
-			Title:       doc.Name,
-			Year:        fmt.Sprintf("%d", doc.Year),
-			Rating:      fmt.Sprintf("%.1f", doc.Rating.Kp),
-			Description: doc.Description,
-			Poster:      doc.Poster.Url,
-		})
+		movies = append(movies, movie)
 	}
+	// Полнотекстовый поиск иногда ничего не находит по международному названию,
+	// тогда пробуем точное совпадение с оригинальным названием
+	if page == 1 && len(movies) == 0 {
+		movies = k.searchByOriginalTitle(ctx, query, limit)
+	}
+	movies = rankByTitle(movies, query)
 	slog.Debug("Ended SearchMovie")
 	return movies, nil
 }
 
-func (k *KinopoiskAPI) SearchPerson(query string, page int) ([]model.Person, error) {
+// searchByOriginalTitle looks movies up by exact alternativeName with a
+// single request. Errors are logged and give no results.
+func (k *KinopoiskAPI) searchByOriginalTitle(ctx context.Context, query string, limit int) []model.Movie {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return nil
+	}
+	params := url.Values{}
+	params.Add("page", "1")
+	params.Add("limit", strconv.Itoa(limit))
+	params.Add("alternativeName", query)
+	searchUrl := fmt.Sprintf("%s/v1.4/movie?%s", k.baseUrl, params.Encode())
+
+	var data MovieResponse
+	if err := k.doRequest(ctx, searchUrl, &data); err != nil {
+		slog.Error("Original title search fetch err:", "error", err)
+		return nil
+	}
+	var movies []model.Movie
+	for _, doc := range data.Docs {
+		if movie := doc.toMovie(); movie.Title != "" {
+			movies = append(movies, movie)
+		}
+	}
+	return movies
+}
+
+func titleMatches(movie model.Movie, query string) bool {
+	return strings.EqualFold(movie.Title, query) || strings.EqualFold(movie.OriginalTitle, query)
+}
+
+// rankByTitle moves movies whose Russian or original title matches the query
+// to the front, so a search by the international title finds the film even
+// when the API ranks it lower.
+func rankByTitle(movies []model.Movie, query string) []model.Movie {
+	query = strings.TrimSpace(query)
+	ranked := make([]model.Movie, 0, len(movies))
+	var rest []model.Movie
+	for _, movie := range movies {
+		if titleMatches(movie, query) {
+			ranked = append(ranked, movie)
+		} else {
+			rest = append(rest, movie)
+		}
+	}
+	return append(ranked, rest...)
+}
+
+func (k *KinopoiskAPI) SearchPerson(query string, page int, limit int) ([]model.Person, error) {
 	slog.Debug("Started SearchPerson")
-	searchUrl := fmt.Sprintf("%s/v1.4/person/search?page=%d&limit=10&query=%s",
-		k.baseUrl, page, url.QueryEscape(query))
+	searchUrl := fmt.Sprintf("%s/v1.4/person/search?page=%d&limit=%d&query=%s",
+		k.baseUrl, page, limit, url.QueryEscape(query))
 
 	var data PersonResponse
-	if err := k.doRequest(searchUrl, &data); err != nil {
-		slog.Error("SearchPerson fetch err:", err)
+	if err := k.doRequest(context.Background(), searchUrl, &data); err != nil {
+		slog.Error("SearchPerson fetch err:", "error", err)
 		return nil, err
 	}
 
 	var persons []model.Person
 	for _, doc := range data.Docs {
-		if doc.Photo == "" || doc.Birthday == "" {
+		if doc.Name == "" && doc.EnName == "" {
 			continue
 		}
-
-		t, err := time.Parse(time.RFC3339, doc.Birthday)
-		if err != nil {
-			panic(err)
-		}
 		persons = append(persons, model.Person{
 			Id:     doc.Id,
 			Name:   doc.Name,
 			EnName: doc.EnName,
 			Sex:    doc.Sex,
 			Photo:  doc.Photo,
-			Birth:  t.Format("02 Jan 2006"),
+			Birth:  formatDate(doc.Birthday),
 		})
 	}
 	slog.Debug("Ended SearchPerson")
 	return persons, nil
 }
 
-func (k *KinopoiskAPI) SearchMoviesByPerson(personId int, page int) ([]model.Movie, error) {
+const (
+	SortByPopularity = "popularity"
+	SortByYear       = "year"
+	SortByRating     = "rating"
+)
+
+var personMoviesSortFields = map[string]string{
+	SortByPopularity: "votes.imdb",
+	SortByYear:       "year",
+	SortByRating:     "rating.kp",
+}
+
+// SearchMoviesByPerson returns a page of the person's movies. A non-empty role
+// limits them to credits in that profession (actor, director, writer,
+// producer). Unknown sort values fall back to sorting by popularity. With
+// hideAdult the API leaves out movies rated 18+, so pages stay full. The
+// request is abandoned once ctx is done.
+func (k *KinopoiskAPI) SearchMoviesByPerson(ctx context.Context, personId int, role string, sort string, page int, limit int, hideAdult bool) ([]model.Movie, error) {
 	slog.Debug("Started SearchMoviesByPerson")
-	searchUrl := fmt.Sprintf("%s/v1.4/movie?page=%d&limit=10&sortField=votes.imdb&sortType=-1&persons.id=%d",
-		k.baseUrl, page, personId)
+	sortField, ok := personMoviesSortFields[sort]
+	if !ok {
+		sortField = personMoviesSortFields[SortByPopularity]
+	}
+	searchUrl := fmt.Sprintf("%s/v1.4/movie?page=%d&limit=%d&sortField=%s&sortType=-1&persons.id=%d",
+		k.baseUrl, page, limit, sortField, personId)
+	if role != "" {
+		searchUrl += "&persons.enProfession=" + url.QueryEscape(role)
+	}
+	if hideAdult {
+		searchUrl += "&ageRating=0-17"
+	}
 
 	var data MovieResponse
-	if err := k.doRequest(searchUrl, &data); err != nil {
-		slog.Error("SearchMoviesByPerson fetch err:", err)
+	if err := k.doRequest(ctx, searchUrl, &data); err != nil {
+		slog.Error("SearchMoviesByPerson fetch err:", "error", err)
 		return nil, err
 	}
 
@@ -129,18 +302,379 @@ func (k *KinopoiskAPI) SearchMoviesByPerson(personId int, page int) ([]model.Mov
 		//if doc.Rating.Kp == 0 || doc.Description == "" || doc.Poster.Url == "" {
 		//	continue
 		//}
-		if doc.Name == "" {
+		movie := doc.toMovie()
+		if movie.Title == "" {
 			continue
 		}
-		movies = append(movies, model.Movie{
-			Id:          doc.Id,
-			Title:       doc.Name,
-			Year:        fmt.Sprintf("%d", doc.Year),
-			Rating:      fmt.Sprintf("%.1f", doc.Rating.Kp),
-			Description: doc.Description,
-			Poster:      doc.Poster.Url,
-		})
+		movie.Character = doc.characterOf(personId)
+		movies = append(movies, movie)
 	}
 	slog.Debug("Ended SearchMoviesByPerson")
 	return movies, nil
 }
+
+func (k *KinopoiskAPI) GetMovieByID(movieId int) (*model.Movie, error) {
+	slog.Debug("Started GetMovieByID")
+	movieUrl := fmt.Sprintf("%s/v1.4/movie/%d", k.baseUrl, movieId)
+
+	var doc MovieDoc
+	if err := k.doRequest(context.Background(), movieUrl, &doc); err != nil {
+		slog.Error("GetMovieByID fetch err:", "error", err)
+		return nil, err
+	}
+
+	movie := doc.toMovie()
+	slog.Debug("Ended GetMovieByID")
+	return &movie, nil
+}
+
+func (doc MovieDoc) toMovie() model.Movie {
+	title, original := titles(doc.Name, doc.AlternativeName, doc.EnName)
+	return model.Movie{
+		Id:            doc.Id,
+		Title:         title,
+		OriginalTitle: original,
+		Year:          doc.years(),
+		Rating:        fmt.Sprintf("%.1f", doc.Rating.Kp),
+		Ratings: model.Ratings{
+			Kp:                      doc.Rating.Kp,
+			KpVotes:                 doc.Votes.Kp,
+			Imdb:                    doc.Rating.Imdb,
+			ImdbVotes:               doc.Votes.Imdb,
+			FilmCritics:             doc.Rating.FilmCritics,
+			FilmCriticsVotes:        doc.Votes.FilmCritics,
+			RussianFilmCritics:      doc.Rating.RussianFilmCritics,
+			RussianFilmCriticsVotes: doc.Votes.RussianFilmCritics,
+			Await:                   doc.Rating.Await,
+			AwaitVotes:              doc.Votes.Await,
+		},
+		Description:  doc.Description,
+		Poster:       doc.Poster.Url,
+		AgeRating:    doc.AgeRating,
+		Similar:      linkedMovies(doc.SimilarMovies),
+		Sequels:      linkedMovies(doc.SequelsAndPrequels),
+		IsSeries:     doc.IsSeries,
+		SeriesLength: doc.SeriesLength,
+		Status:       doc.Status,
+		WatchOptions: doc.watchOptions(),
+		Trailers:     doc.trailers(),
+	}
+}
+
+func (doc MovieDoc) trailers() []model.Trailer {
+	var trailers []model.Trailer
+	for _, video := range doc.Videos.Trailers {
+		if video.Url == "" {
+			continue
+		}
+		trailers = append(trailers, model.Trailer{
+			Name: video.Name,
+			// Embed links don't get a preview in Telegram, watch links do.
+			URL:  strings.Replace(video.Url, "youtube.com/embed/", "youtube.com/watch?v=", 1),
+			Site: strings.ToLower(video.Site),
+		})
+	}
+	return trailers
+}
+
+func (doc MovieDoc) watchOptions() []model.WatchOption {
+	var options []model.WatchOption
+	for _, item := range doc.Watchability.Items {
+		if item.Name == "" || item.Url == "" {
+			continue
+		}
+		options = append(options, model.WatchOption{
+			Name: item.Name,
+			Logo: item.Logo.Url,
+			URL:  item.Url,
+		})
+	}
+	return options
+}
+
+// years returns the release year, or the range of years a series ran for.
+func (doc MovieDoc) years() string {
+	if !doc.IsSeries || len(doc.ReleaseYears) == 0 {
+		return fmt.Sprintf("%d", doc.Year)
+	}
+
+	start, end := doc.ReleaseYears[0].Start, doc.ReleaseYears[0].End
+	for _, years := range doc.ReleaseYears[1:] {
+		if years.Start != 0 && (start == 0 || years.Start < start) {
+			start = years.Start
+		}
+		if years.End > end {
+			end = years.End
+		}
+	}
+	switch {
+	case start == 0:
+		return fmt.Sprintf("%d", doc.Year)
+	case end == 0:
+		return fmt.Sprintf("%d–…", start)
+	case end == start:
+		return fmt.Sprintf("%d", start)
+	default:
+		return fmt.Sprintf("%d–%d", start, end)
+	}
+}
+
+// characterOf returns the name of the character the person plays in the movie.
+func (doc MovieDoc) characterOf(personId int) string {
+	for _, person := range doc.Persons {
+		if person.Id == personId && person.EnProfession == "actor" {
+			return person.Description
+		}
+	}
+	return ""
+}
+
+// AgeRatings returns the age ratings of the given movies, which similar
+// movies and sequels come without. Movies without a rating are left out.
+func (k *KinopoiskAPI) AgeRatings(movieIds []int) (map[int]int, error) {
+	ratings := make(map[int]int, len(movieIds))
+	if len(movieIds) == 0 {
+		return ratings, nil
+	}
+	params := url.Values{}
+	params.Add("page", "1")
+	params.Add("limit", strconv.Itoa(len(movieIds)))
+	params.Add("selectFields", "id")
+	params.Add("selectFields", "ageRating")
+	for _, id := range movieIds {
+		params.Add("id", strconv.Itoa(id))
+	}
+	ratingsUrl := fmt.Sprintf("%s/v1.4/movie?%s", k.baseUrl, params.Encode())
+
+	var data MovieResponse
+	if err := k.doRequest(context.Background(), ratingsUrl, &data); err != nil {
+		slog.Error("AgeRatings fetch err:", "error", err)
+		return nil, err
+	}
+	for _, doc := range data.Docs {
+		if doc.AgeRating > 0 {
+			ratings[doc.Id] = doc.AgeRating
+		}
+	}
+	return ratings, nil
+}
+
+func linkedMovies(docs []LinkedMovie) []model.Movie {
+	var movies []model.Movie
+	for _, doc := range docs {
+		title, original := titles(doc.Name, doc.AlternativeName, doc.EnName)
+		if title == "" {
+			continue
+		}
+		movies = append(movies, model.Movie{
+			Id:            doc.Id,
+			Title:         title,
+			OriginalTitle: original,
+			Year:          fmt.Sprintf("%d", doc.Year),
+			Rating:        fmt.Sprintf("%.1f", doc.Rating.Kp),
+			Ratings:       model.Ratings{Kp: doc.Rating.Kp},
+			Poster:        doc.Poster.Url,
+		})
+	}
+	return movies
+}
+
+// titles returns the Russian and original titles of a movie. Movies without
+// a Russian title are titled by their original one.
+func titles(name string, alternativeName string, enName string) (string, string) {
+	original := alternativeName
+	if original == "" {
+		original = enName
+	}
+	if name == "" {
+		return original, original
+	}
+	return name, original
+}
+
+// RandomMovie returns a random movie matching filter. With hideAdult the API
+// leaves out movies rated 18+.
+func (k *KinopoiskAPI) RandomMovie(filter model.RandomFilter, hideAdult bool) (*model.Movie, error) {
+	slog.Debug("Started RandomMovie")
+	params := url.Values{}
+	params.Add("notNullFields", "name")
+	if filter.Genre != "" {
+		params.Add("genres.name", filter.Genre)
+	}
+	if filter.MinRating > 0 {
+		params.Add("rating.kp", fmt.Sprintf("%g-10", filter.MinRating))
+	}
+	if filter.YearFrom > 0 || filter.YearTo > 0 {
+		yearTo := filter.YearTo
+		if yearTo == 0 {
+			yearTo = time.Now().Year()
+		}
+		params.Add("year", fmt.Sprintf("%d-%d", filter.YearFrom, yearTo))
+	}
+	if hideAdult {
+		params.Add("ageRating", "0-17")
+	}
+	randomUrl := fmt.Sprintf("%s/v1.4/movie/random?%s", k.baseUrl, params.Encode())
+
+	var doc MovieDoc
+	if err := k.doUnsharedRequest(randomUrl, &doc); err != nil {
+		slog.Error("RandomMovie fetch err:", "error", err)
+		return nil, err
+	}
+	if doc.Id == 0 {
+		return nil, nil
+	}
+
+	movie := doc.toMovie()
+	slog.Debug("Ended RandomMovie")
+	return &movie, nil
+}
+
+func (k *KinopoiskAPI) GetPersonByID(personId int) (*model.Person, error) {
+	slog.Debug("Started GetPersonByID")
+	personUrl := fmt.Sprintf("%s/v1.4/person/%d", k.baseUrl, personId)
+
+	var doc PersonDoc
+	if err := k.doRequest(context.Background(), personUrl, &doc); err != nil {
+		slog.Error("GetPersonByID fetch err:", "error", err)
+		return nil, err
+	}
+
+	person := model.Person{
+		Id:         doc.Id,
+		Name:       doc.Name,
+		EnName:     doc.EnName,
+		Sex:        doc.Sex,
+		Photo:      doc.Photo,
+		Birth:      formatDate(doc.Birthday),
+		Death:      formatDate(doc.Death),
+		Age:        doc.Age,
+		Height:     doc.Growth,
+		RoleCounts: make(map[string]int),
+	}
+	var places []string
+	for _, place := range doc.BirthPlace {
+		places = append(places, place.Value)
+	}
+	person.BirthPlace = strings.Join(places, ", ")
+	for _, profession := range doc.Profession {
+		person.Professions = append(person.Professions, profession.Value)
+	}
+	for _, fact := range doc.Facts {
+		person.Facts = append(person.Facts, fact.Value)
+	}
+	for _, movie := range doc.Movies {
+		person.RoleCounts[movie.EnProfession]++
+	}
+
+	slog.Debug("Ended GetPersonByID")
+	return &person, nil
+}
+
+// formatDate converts an API timestamp to the bot's date format. Unknown or
+// malformed dates become an empty string.
+func formatDate(value string) string {
+	if value == "" {
+		return ""
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		slog.Warn("Invalid date in API response", "value", value)
+		return ""
+	}
+	return t.Format("02 Jan 2006")
+}
+
+func (k *KinopoiskAPI) GetSeasons(movieId int) ([]model.Season, error) {
+	slog.Debug("Started GetSeasons")
+	seasonsUrl := fmt.Sprintf("%s/v1.4/season?page=1&limit=100&sortField=number&sortType=1&movieId=%d",
+		k.baseUrl, movieId)
+
+	var data SeasonResponse
+	if err := k.doRequest(context.Background(), seasonsUrl, &data); err != nil {
+		slog.Error("GetSeasons fetch err:", "error", err)
+		return nil, err
+	}
+
+	var seasons []model.Season
+	for _, doc := range data.Docs {
+		season := model.Season{
+			Number:        doc.Number,
+			Name:          doc.Name,
+			EpisodesCount: doc.EpisodesCount,
+		}
+		for _, episode := range doc.Episodes {
+			name := episode.Name
+			if name == "" {
+				name = episode.EnName
+			}
+			airDate := episode.AirDate
+			if airDate == "" {
+				airDate = episode.Date
+			}
+			season.Episodes = append(season.Episodes, model.Episode{
+				Number:      episode.Number,
+				Name:        name,
+				AirDate:     formatDate(airDate),
+				Description: episode.Description,
+			})
+		}
+		if season.EpisodesCount == 0 {
+			season.EpisodesCount = len(season.Episodes)
+		}
+		seasons = append(seasons, season)
+	}
+	slog.Debug("Ended GetSeasons")
+	return seasons, nil
+}
+
+// GetReviews returns a page of user reviews for a movie and the total number of pages.
+func (k *KinopoiskAPI) GetReviews(movieId int, page int, limit int) ([]model.Review, int, error) {
+	slog.Debug("Started GetReviews")
+	reviewsUrl := fmt.Sprintf("%s/v1.4/review?page=%d&limit=%d&sortField=date&sortType=-1&movieId=%d",
+		k.baseUrl, page, limit, movieId)
+
+	reviews, pages, err := k.fetchReviews(reviewsUrl)
+	if err != nil {
+		slog.Error("GetReviews fetch err:", "error", err)
+		return nil, 0, err
+	}
+	slog.Debug("Ended GetReviews")
+	return reviews, pages, nil
+}
+
+func (k *KinopoiskAPI) GetReviewByID(reviewId int) (*model.Review, error) {
+	slog.Debug("Started GetReviewByID")
+	reviewUrl := fmt.Sprintf("%s/v1.4/review?page=1&limit=1&id=%d", k.baseUrl, reviewId)
+
+	reviews, _, err := k.fetchReviews(reviewUrl)
+	if err != nil {
+		slog.Error("GetReviewByID fetch err:", "error", err)
+		return nil, err
+	}
+	if len(reviews) == 0 {
+		return nil, fmt.Errorf("review %d not found", reviewId)
+	}
+	slog.Debug("Ended GetReviewByID")
+	return &reviews[0], nil
+}
+
+func (k *KinopoiskAPI) fetchReviews(reviewsUrl string) ([]model.Review, int, error) {
+	var data ReviewResponse
+	if err := k.doRequest(context.Background(), reviewsUrl, &data); err != nil {
+		return nil, 0, err
+	}
+
+	var reviews []model.Review
+	for _, doc := range data.Docs {
+		reviews = append(reviews, model.Review{
+			Id:     doc.Id,
+			Title:  doc.Title,
+			Type:   doc.Type,
+			Text:   doc.Review,
+			Author: doc.Author,
+			Date:   formatDate(doc.Date),
+		})
+	}
+	return reviews, data.Pages, nil
+}
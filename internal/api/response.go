@@ -1,18 +1,110 @@
 package api
 
-type MovieResponse struct {
-	Docs []struct {
-		Id          int    `json:"id"`
-		Name        string `json:"name"`
-		Year        int    `json:"year"`
-		Description string `json:"description"`
-		Poster      struct {
+type MovieDoc struct {
+	Id              int    `json:"id"`
+	Name            string `json:"name"`
+	AlternativeName string `json:"alternativeName"`
+	EnName          string `json:"enName"`
+	Year            int    `json:"year"`
+	AgeRating       int    `json:"ageRating"`
+	Description     string `json:"description"`
+	Poster          struct {
+		Url string `json:"url"`
+	} `json:"poster"`
+	Rating       RatingInfo `json:"rating"`
+	Votes        VotesInfo  `json:"votes"`
+	IsSeries     bool       `json:"isSeries"`
+	SeriesLength int        `json:"seriesLength"`
+	Status       string     `json:"status"`
+	ReleaseYears []struct {
+		Start int `json:"start"`
+		End   int `json:"end"`
+	} `json:"releaseYears"`
+	Persons []struct {
+		Id           int    `json:"id"`
+		Description  string `json:"description"`
+		EnProfession string `json:"enProfession"`
+	} `json:"persons"`
+	Watchability struct {
+		Items []struct {
+			Name string `json:"name"`
+			Logo struct {
+				Url string `json:"url"`
+			} `json:"logo"`
 			Url string `json:"url"`
-		} `json:"poster"`
-		Rating struct {
-			Kp float64 `json:"kp"`
-		} `json:"rating"`
-	} `json:"docs"`
+		} `json:"items"`
+	} `json:"watchability"`
+	Videos struct {
+		Trailers []struct {
+			Url  string `json:"url"`
+			Name string `json:"name"`
+			Site string `json:"site"`
+		} `json:"trailers"`
+	} `json:"videos"`
+	SimilarMovies      []LinkedMovie `json:"similarMovies"`
+	SequelsAndPrequels []LinkedMovie `json:"sequelsAndPrequels"`
+}
+
+type RatingInfo struct {
+	Kp                 float64 `json:"kp"`
+	Imdb               float64 `json:"imdb"`
+	FilmCritics        float64 `json:"filmCritics"`
+	RussianFilmCritics float64 `json:"russianFilmCritics"`
+	Await              float64 `json:"await"`
+}
+
+type VotesInfo struct {
+	Kp                 int `json:"kp"`
+	Imdb               int `json:"imdb"`
+	FilmCritics        int `json:"filmCritics"`
+	RussianFilmCritics int `json:"russianFilmCritics"`
+	Await              int `json:"await"`
+}
+
+type LinkedMovie struct {
+	Id              int    `json:"id"`
+	Name            string `json:"name"`
+	AlternativeName string `json:"alternativeName"`
+	EnName          string `json:"enName"`
+	Year            int    `json:"year"`
+	Type            string `json:"type"`
+	Poster          struct {
+		Url string `json:"url"`
+	} `json:"poster"`
+	Rating struct {
+		Kp float64 `json:"kp"`
+	} `json:"rating"`
+}
+
+type MovieResponse struct {
+	Docs []MovieDoc `json:"docs"`
+}
+
+type PersonDoc struct {
+	Id         int    `json:"id"`
+	Name       string `json:"name"`
+	EnName     string `json:"enName"`
+	Photo      string `json:"photo"`
+	Sex        string `json:"sex"`
+	Growth     int    `json:"growth"`
+	Birthday   string `json:"birthday"`
+	Death      string `json:"death"`
+	Age        int    `json:"age"`
+	BirthPlace []struct {
+		Value string `json:"value"`
+	} `json:"birthPlace"`
+	Profession []struct {
+		Value string `json:"value"`
+	} `json:"profession"`
+	Facts []struct {
+		Value string `json:"value"`
+	} `json:"facts"`
+	Movies []struct {
+		Id           int    `json:"id"`
+		Name         string `json:"name"`
+		Description  string `json:"description"`
+		EnProfession string `json:"enProfession"`
+	} `json:"movies"`
 }
 
 type PersonResponse struct {
@@ -25,3 +117,31 @@ type PersonResponse struct {
 		Birthday string `json:"birthday"`
 	} `json:"docs"`
 }
+
+type SeasonResponse struct {
+	Docs []struct {
+		Number        int    `json:"number"`
+		Name          string `json:"name"`
+		EpisodesCount int    `json:"episodesCount"`
+		Episodes      []struct {
+			Number      int    `json:"number"`
+			Name        string `json:"name"`
+			EnName      string `json:"enName"`
+			Date        string `json:"date"`
+			AirDate     string `json:"airDate"`
+			Description string `json:"description"`
+		} `json:"episodes"`
+	} `json:"docs"`
+}
+
+type ReviewResponse struct {
+	Docs []struct {
+		Id     int    `json:"id"`
+		Title  string `json:"title"`
+		Type   string `json:"type"`
+		Review string `json:"review"`
+		Author string `json:"author"`
+		Date   string `json:"date"`
+	} `json:"docs"`
+	Pages int `json:"pages"`
+}
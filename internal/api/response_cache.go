@@ -0,0 +1,96 @@
+package api
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// responseCacheMaxEntries caps the number of cached responses. Once it is
+// reached, the least recently used response is dropped.
+const responseCacheMaxEntries = 1000
+
+// responseCache keeps recent response bodies by URL for a fixed TTL.
+type responseCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	order      *list.List // от недавно использованных к давно использованным
+	entries    map[string]*list.Element
+}
+
+type cachedResponse struct {
+	url       string
+	body      []byte
+	expiresAt time.Time
+}
+
+func newResponseCache(maxEntries int) *responseCache {
+	return &responseCache{
+		maxEntries: maxEntries,
+		order:      list.New(),
+		entries:    make(map[string]*list.Element),
+	}
+}
+
+// Get returns the cached body for url unless it has expired.
+func (c *responseCache) Get(url string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	element, ok := c.entries[url]
+	if !ok {
+		return nil, false
+	}
+	response := element.Value.(cachedResponse)
+	if time.Now().After(response.expiresAt) {
+		c.remove(element)
+		return nil, false
+	}
+	c.order.MoveToFront(element)
+	return response.body, true
+}
+
+func (c *responseCache) Put(url string, body []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[url]; ok {
+		c.remove(element)
+	}
+	c.entries[url] = c.order.PushFront(cachedResponse{url: url, body: body, expiresAt: time.Now().Add(ttl)})
+	for c.order.Len() > c.maxEntries {
+		c.remove(c.order.Back())
+	}
+}
+
+// remove drops a cached response. The caller must hold c.mu.
+func (c *responseCache) remove(element *list.Element) {
+	c.order.Remove(element)
+	delete(c.entries, element.Value.(cachedResponse).url)
+}
+
+// ClearExpired drops expired responses.
+func (c *responseCache) ClearExpired() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := time.Now()
+	for _, element := range c.entries {
+		if now.After(element.Value.(cachedResponse).expiresAt) {
+			c.remove(element)
+		}
+	}
+}
+
+// Clear drops all responses and returns how many there were.
+func (c *responseCache) Clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := c.order.Len()
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+	return count
+}
+
+func (c *responseCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
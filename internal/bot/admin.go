@@ -0,0 +1,221 @@
+package bot
+
+import (
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"log/slog"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Config holds the bot options that /reload can change at runtime.
+type Config struct {
+	// Admins are the Telegram user IDs allowed to use admin commands.
+	Admins []int64
+	// APIDailyQuota is the kinopoisk.dev daily request limit, 0 if unknown.
+	APIDailyQuota int
+}
+
+// adminCommands are handled only for admins and ignored for everyone else.
+var adminCommands = []string{"stats", "cache_clear", "ban", "unban", "maintenance", "reload", "broadcast"}
+
+func (b *Bot) isAdmin(user *tgbotapi.User) bool {
+	return user != nil && slices.Contains(b.config.Load().Admins, user.ID)
+}
+
+// checkAccess reports whether the user may use the bot right now. When not,
+// notice is the message key to answer with, or empty for banned users, who
+// are ignored silently. Admins are never restricted.
+func (b *Bot) checkAccess(user *tgbotapi.User) (allowed bool, notice string) {
+	if user == nil || b.isAdmin(user) {
+		return true, ""
+	}
+	banned, err := b.redis.IsBanned(user.ID)
+	if err != nil {
+		slog.Error("Error checking ban in Redis", "error", err)
+	}
+	if banned {
+		return false, ""
+	}
+	maintenance, err := b.redis.IsMaintenance()
+	if err != nil {
+		slog.Error("Error checking maintenance mode in Redis", "error", err)
+	}
+	if maintenance {
+		return false, "maintenance.notice"
+	}
+	return true, ""
+}
+
+func (b *Bot) handleAdminCommand(msg *tgbotapi.Message) {
+	if !b.isAdmin(msg.From) {
+		return
+	}
+	slog.Info("Admin command", "user_id", msg.From.ID, "command", msg.Command(), "args", msg.CommandArguments())
+
+	lang := b.userLang(msg.From)
+	chatID := msg.Chat.ID
+	switch msg.Command() {
+	case "stats":
+		b.sendText(chatID, b.formatStats(lang))
+	case "cache_clear":
+		b.handleCacheClear(chatID, lang)
+	case "ban", "unban":
+		b.handleBan(msg, lang, msg.Command() == "ban")
+	case "maintenance":
+		b.handleMaintenance(chatID, lang, strings.TrimSpace(msg.CommandArguments()))
+	case "reload":
+		b.handleReload(chatID, lang)
+	case "broadcast":
+		b.handleBroadcastCommand(chatID, lang, strings.TrimSpace(msg.CommandArguments()))
+	}
+}
+
+func (b *Bot) formatStats(lang string) string {
+	var sb strings.Builder
+	sb.WriteString(tr(lang, "admin.stats.title") + "\n\n")
+
+	users, inactive, err := b.redis.CountUsers()
+	if err != nil {
+		slog.Error("Error counting users in Redis", "error", err)
+	}
+	sb.WriteString(tr(lang, "admin.stats.users", users, inactive) + "\n")
+
+	banned, err := b.redis.CountBanned()
+	if err != nil {
+		slog.Error("Error counting banned users in Redis", "error", err)
+	}
+	sb.WriteString(tr(lang, "admin.stats.banned", banned) + "\n")
+
+	searches, err := b.redis.GetSearchStats()
+	if err != nil {
+		slog.Error("Error getting search stats from Redis", "error", err)
+	}
+	var total int64
+	types := make([]string, 0, len(searches))
+	for searchType, count := range searches {
+		total += count
+		types = append(types, searchType)
+	}
+	sort.Strings(types)
+	sb.WriteString(tr(lang, "admin.stats.searches", total) + "\n")
+	for _, searchType := range types {
+		sb.WriteString(fmt.Sprintf("  %s: %d\n", searchType, searches[searchType]))
+	}
+
+	entries, size := imageCacheStats()
+	sb.WriteString(tr(lang, "admin.stats.image_cache", entries, formatBytes(size)) + "\n")
+	if posterDiskCache != nil {
+		files, size := posterDiskCache.Stats()
+		sb.WriteString(tr(lang, "admin.stats.disk_cache", files, formatBytes(size)) + "\n")
+	}
+	sb.WriteString(tr(lang, "admin.stats.response_cache", b.kinopoisk.ResponseCacheSize()) + "\n")
+
+	requests := b.kinopoisk.RequestsToday()
+	if quota := b.config.Load().APIDailyQuota; quota > 0 {
+		sb.WriteString(tr(lang, "admin.stats.api_quota", requests, quota) + "\n")
+	} else {
+		sb.WriteString(tr(lang, "admin.stats.api_requests", requests) + "\n")
+	}
+
+	maintenance, err := b.redis.IsMaintenance()
+	if err != nil {
+		slog.Error("Error checking maintenance mode in Redis", "error", err)
+	}
+	if maintenance {
+		sb.WriteString(tr(lang, "admin.maintenance.on"))
+	} else {
+		sb.WriteString(tr(lang, "admin.maintenance.off"))
+	}
+	return sb.String()
+}
+
+func formatBytes(size int64) string {
+	const unit = 1024
+	switch {
+	case size >= unit*unit:
+		return fmt.Sprintf("%.1f MB", float64(size)/(unit*unit))
+	case size >= unit:
+		return fmt.Sprintf("%.1f KB", float64(size)/unit)
+	default:
+		return fmt.Sprintf("%d B", size)
+	}
+}
+
+func (b *Bot) handleCacheClear(chatID int64, lang string) {
+	images := clearImageCache()
+	files := 0
+	if posterDiskCache != nil {
+		files = posterDiskCache.Clear()
+	}
+	responses := b.kinopoisk.ClearResponseCache()
+	slog.Info("Caches cleared by admin", "images", images, "files", files, "responses", responses)
+	b.sendText(chatID, tr(lang, "admin.cache_cleared", images, files, responses))
+}
+
+// handleBan bans or unbans the user given by ID or by replying to their
+// message.
+func (b *Bot) handleBan(msg *tgbotapi.Message, lang string, ban bool) {
+	var userID int64
+	if args := strings.TrimSpace(msg.CommandArguments()); args != "" {
+		id, err := strconv.ParseInt(args, 10, 64)
+		if err != nil {
+			b.sendText(msg.Chat.ID, tr(lang, "admin.ban.usage"))
+			return
+		}
+		userID = id
+	} else if msg.ReplyToMessage != nil && msg.ReplyToMessage.From != nil {
+		userID = msg.ReplyToMessage.From.ID
+	} else {
+		b.sendText(msg.Chat.ID, tr(lang, "admin.ban.usage"))
+		return
+	}
+
+	if ban {
+		if slices.Contains(b.config.Load().Admins, userID) {
+			b.sendText(msg.Chat.ID, tr(lang, "admin.ban.admin"))
+			return
+		}
+		if err := b.redis.Ban(userID); err != nil {
+			slog.Error("Error banning user in Redis", "error", err)
+			return
+		}
+		b.sendText(msg.Chat.ID, tr(lang, "admin.banned", userID))
+		return
+	}
+	if err := b.redis.Unban(userID); err != nil {
+		slog.Error("Error unbanning user in Redis", "error", err)
+		return
+	}
+	b.sendText(msg.Chat.ID, tr(lang, "admin.unbanned", userID))
+}
+
+func (b *Bot) handleMaintenance(chatID int64, lang string, mode string) {
+	switch mode {
+	case "on", "off":
+		if err := b.redis.SetMaintenance(mode == "on"); err != nil {
+			slog.Error("Error saving maintenance mode to Redis", "error", err)
+			return
+		}
+		b.sendText(chatID, tr(lang, "admin.maintenance."+mode))
+	default:
+		b.sendText(chatID, tr(lang, "admin.maintenance.usage"))
+	}
+}
+
+func (b *Bot) handleReload(chatID int64, lang string) {
+	if b.reload == nil {
+		return
+	}
+	config, err := b.reload()
+	if err != nil {
+		slog.Error("Error reloading config", "error", err)
+		b.sendText(chatID, tr(lang, "admin.reload_failed", err))
+		return
+	}
+	b.config.Store(&config)
+	slog.Info("Config reloaded", "admins", len(config.Admins))
+	b.sendText(chatID, tr(lang, "admin.reloaded"))
+}
@@ -5,7 +5,9 @@ import (
 	"kinopoisk-bot/internal/api"
 	"kinopoisk-bot/internal/redis"
 	"log/slog"
+	"slices"
 	"sync"
+	"sync/atomic"
 )
 
 const (
@@ -13,28 +15,46 @@ const (
 	searchTypeMovie        = "movie"
 	searchTypePerson       = "person"
 	searchTypePersonMovies = "person_movies"
+	searchTypeSimilar      = "similar"
+	searchTypeSequels      = "sequels"
+	defaultResultsPerPage  = 10
 )
 
 type Bot struct {
-	api       *tgbotapi.BotAPI
+	api *tgbotapi.BotAPI
+	// sender makes all outbound API calls within Telegram's limits
+	sender *sender
+	// handlers process the updates of each chat in order, chats in parallel
+	handlers  *chatWorkers
 	kinopoisk *api.KinopoiskAPI
 	redis     *redis.RedisClient
 	stopChan  chan struct{}  // Channel to signal stopping
 	wg        sync.WaitGroup // WaitGroup for graceful shutdown
+	// prefetches holds the running next page prefetch of each chat
+	prefetches sync.Map
+	config     atomic.Pointer[Config]
+	// reload rereads the configuration for /reload
+	reload func() (Config, error)
 }
 
-func NewBot(token string, redisClient *redis.RedisClient, kinopoiskAPI *api.KinopoiskAPI) (*Bot, error) {
+func NewBot(token string, redisClient *redis.RedisClient, kinopoiskAPI *api.KinopoiskAPI,
+	config Config, reload func() (Config, error)) (*Bot, error) {
 	botAPI, err := tgbotapi.NewBotAPI(token)
 	if err != nil {
 		return nil, err
 	}
 
-	return &Bot{
+	b := &Bot{
 		api:       botAPI,
+		sender:    newSender(botAPI),
+		handlers:  newChatWorkers(),
 		kinopoisk: kinopoiskAPI,
 		redis:     redisClient,
 		stopChan:  make(chan struct{}),
-	}, nil
+		reload:    reload,
+	}
+	b.config.Store(&config)
+	return b, nil
 }
 
 func (b *Bot) Start() {
@@ -44,6 +64,9 @@ func (b *Bot) Start() {
 	u.Timeout = 60
 	updates := b.api.GetUpdatesChan(u)
 
+	b.wg.Add(1)
+	go b.watchMovieNights()
+
 	b.wg.Add(1)
 	defer b.wg.Done()
 
@@ -58,27 +81,107 @@ func (b *Bot) Start() {
 				return
 			}
 
-			if update.CallbackQuery != nil {
-				b.handleCallbackQuery(update.CallbackQuery)
-				continue
-			}
+			b.dispatchUpdate(update)
+		}
+	}
+}
 
-			if update.Message == nil {
-				continue
-			}
+// dispatchUpdate hands the update to its chat's goroutine, so a chat waiting
+// on Telegram's limits doesn't hold up updates from other chats. Updates
+// without a chat share one goroutine. When a chat floods the bot faster than
+// its updates are handled, the excess updates are dropped.
+func (b *Bot) dispatchUpdate(update tgbotapi.Update) {
+	var chatID int64
+	if chat := update.FromChat(); chat != nil {
+		chatID = chat.ID
+	}
+	b.wg.Add(1)
+	queued := b.handlers.TryGo(chatID, func() {
+		defer b.wg.Done()
+		b.handleUpdate(update)
+	})
+	if queued {
+		return
+	}
+	slog.Warn("Chat update queue is full, dropping update", "chat", chatID, "update", update.UpdateID)
+	if query := update.CallbackQuery; query != nil {
+		// Ответ не ждет очереди чата, иначе кнопка так и останется с часиками
+		go func() {
+			defer b.wg.Done()
+			b.answerCallback(query, tr(b.userLang(query.From), "rate_limit.busy"))
+		}()
+		return
+	}
+	b.wg.Done()
+}
 
-			if !update.Message.IsCommand() {
-				b.handleMessage(update.Message)
-				continue
-			}
+func (b *Bot) handleUpdate(update tgbotapi.Update) {
+	if chat := update.FromChat(); chat != nil {
+		b.touchPrefetch(chat.ID)
+	}
+	// В группах бот не может писать первым, поэтому там пользователи регистрируются только через /start
+	if chat := update.FromChat(); chat != nil && chat.IsPrivate() {
+		b.registerUser(update.SentFrom())
+	}
 
-			switch update.Message.Command() {
-			case "start":
-				b.handleStartCommand(update.Message)
-			case "help":
-				b.handleHelpCommand(update.Message)
+	if query := update.CallbackQuery; query != nil {
+		if allowed, notice := b.checkAccess(query.From); !allowed {
+			if notice != "" {
+				b.answerCallbackAlert(query, tr(b.userLang(query.From), notice))
 			}
+			return
 		}
+		b.handleCallbackQuery(query)
+		return
+	}
+
+	msg := update.Message
+	if msg == nil || msg.From == nil {
+		return
+	}
+
+	if msg.IsCommand() && !b.isCommandForBot(msg) {
+		return
+	}
+	if !msg.IsCommand() && isGroupChat(msg.Chat) && !b.isAddressedToBot(msg) {
+		return
+	}
+	if allowed, notice := b.checkAccess(msg.From); !allowed {
+		if notice != "" {
+			b.sendText(msg.Chat.ID, tr(b.userLang(msg.From), notice))
+		}
+		return
+	}
+
+	if !msg.IsCommand() {
+		b.processSearchQuery(msg)
+		return
+	}
+
+	if slices.Contains(adminCommands, msg.Command()) {
+		b.handleAdminCommand(msg)
+		return
+	}
+
+	switch msg.Command() {
+	case "start":
+		b.handleStartCommand(msg)
+	case "help":
+		b.handleHelpCommand(msg)
+	case "search":
+		b.handleSearchCommand(msg, searchTypeMovie)
+	case "person":
+		b.handleSearchCommand(msg, searchTypePerson)
+	case "movienight":
+		b.handleMovieNightCommand(msg)
+	case "random":
+		b.handleRandomCommand(msg.Chat.ID, msg.From)
+	case "language":
+		b.handleLanguageCommand(msg.Chat.ID, b.userLang(msg.From))
+	case "titles":
+		b.handleTitlesCommand(msg.Chat.ID, b.userLang(msg.From), msg.From.ID)
+	case "settings":
+		b.handleSettingsCommand(msg.Chat.ID, msg.From)
 	}
 }
 
@@ -94,3 +197,13 @@ func (b *Bot) Stop() {
 
 	slog.Info("Bot shutdown complete")
 }
+
+// registerUser adds the user to the registry announcements are sent to.
+func (b *Bot) registerUser(user *tgbotapi.User) {
+	if user == nil || user.IsBot {
+		return
+	}
+	if err := b.redis.RegisterUser(user.ID); err != nil {
+		slog.Error("Error registering user in Redis", "error", err)
+	}
+}
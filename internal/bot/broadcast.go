@@ -0,0 +1,164 @@
+package bot
+
+import (
+	"errors"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// broadcastRate stays below Telegram's limit of about 30 messages per second
+// across all chats, leaving room for regular replies.
+const broadcastRate = 25
+
+// Descriptions Telegram gives for 403 Forbidden when a user can't be sent to.
+const (
+	forbiddenBlocked    = "bot was blocked by the user"
+	forbiddenNotStarted = "bot can't initiate conversation with a user"
+)
+
+type broadcastStats struct {
+	sent    int
+	blocked int
+	// notStarted counts users who never opened a private chat with the bot
+	notStarted int
+	banned     int
+	optedOut   int
+	failed     int
+}
+
+// handleBroadcastCommand shows the announcement as users will see it, with
+// buttons to send or discard it.
+func (b *Bot) handleBroadcastCommand(chatID int64, lang string, text string) {
+	if text == "" {
+		b.sendText(chatID, tr(lang, "broadcast.usage"))
+		return
+	}
+
+	id := newSessionID()
+	if err := b.redis.SaveBroadcast(id, text); err != nil {
+		slog.Error("Error saving broadcast to Redis", "error", err)
+		return
+	}
+
+	b.sendText(chatID, tr(lang, "broadcast.preview"))
+	preview := tgbotapi.NewMessage(chatID, text)
+	preview.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr(lang, "broadcast.send"), "broadcast:send:"+id),
+		tgbotapi.NewInlineKeyboardButtonData(tr(lang, "broadcast.cancel"), "broadcast:cancel:"+id),
+	))
+	if _, err := b.sender.Send(preview); err != nil {
+		slog.Error("Error sending broadcast preview", "error", err)
+	}
+}
+
+// handleBroadcastAction handles the preview's "broadcast:<send|cancel>:<id>"
+// buttons. The draft is taken from Redis first, so a double tap can't send
+// the announcement twice.
+func (b *Bot) handleBroadcastAction(query *tgbotapi.CallbackQuery, lang string, action string, id string) {
+	if !b.isAdmin(query.From) {
+		return
+	}
+	chatID := query.Message.Chat.ID
+	removeKeyboard := tgbotapi.NewEditMessageReplyMarkup(chatID, query.Message.MessageID,
+		tgbotapi.InlineKeyboardMarkup{InlineKeyboard: [][]tgbotapi.InlineKeyboardButton{}})
+	if _, err := b.sender.Request(removeKeyboard); err != nil {
+		slog.Error("Error removing broadcast buttons", "error", err)
+	}
+
+	text, err := b.redis.TakeBroadcast(id)
+	if err != nil {
+		slog.Error("Error getting broadcast from Redis", "error", err)
+		return
+	}
+	if text == "" {
+		b.sendText(chatID, tr(lang, "broadcast.expired"))
+		return
+	}
+
+	switch action {
+	case "send":
+		b.sendText(chatID, tr(lang, "broadcast.started"))
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			stats := b.broadcast(text)
+			b.sendText(chatID, tr(lang, "broadcast.finished",
+				stats.sent, stats.blocked, stats.notStarted, stats.banned, stats.optedOut, stats.failed))
+		}()
+	case "cancel":
+		b.sendText(chatID, tr(lang, "broadcast.cancelled"))
+	}
+}
+
+// broadcast sends text to every active user who isn't banned and hasn't
+// opted out of announcements. Users the bot can't write to are marked
+// inactive.
+func (b *Bot) broadcast(text string) broadcastStats {
+	var stats broadcastStats
+	users, err := b.redis.GetActiveUsers()
+	if err != nil {
+		slog.Error("Error getting users from Redis", "error", err)
+		return stats
+	}
+	slog.Info("Broadcast started", "users", len(users))
+
+	ticker := time.NewTicker(time.Second / broadcastRate)
+	defer ticker.Stop()
+
+	for _, userID := range users {
+		banned, err := b.redis.IsBanned(userID)
+		if err != nil {
+			slog.Error("Error checking ban in Redis", "error", err)
+		}
+		if banned {
+			stats.banned++
+			continue
+		}
+		if !b.loadSettings(userID).NotifyAnnouncements {
+			stats.optedOut++
+			continue
+		}
+
+		select {
+		case <-ticker.C:
+		case <-b.stopChan:
+			slog.Warn("Broadcast interrupted by shutdown", "sent", stats.sent)
+			return stats
+		}
+
+		_, err = b.sender.Send(tgbotapi.NewMessage(userID, text))
+		var tgErr *tgbotapi.Error
+		if err == nil {
+			stats.sent++
+			continue
+		}
+		if !errors.As(err, &tgErr) || tgErr.Code != http.StatusForbidden {
+			stats.failed++
+			slog.Warn("Error sending announcement", "user_id", userID, "error", err)
+			continue
+		}
+
+		switch {
+		case strings.Contains(tgErr.Message, forbiddenBlocked):
+			stats.blocked++
+		case strings.Contains(tgErr.Message, forbiddenNotStarted):
+			stats.notStarted++
+		default:
+			// Например, аккаунт удален
+			stats.failed++
+			slog.Warn("Announcement forbidden", "user_id", userID, "error", err)
+		}
+		// Написать пользователю можно будет, только когда он сам напишет боту
+		if err := b.redis.MarkUserInactive(userID); err != nil {
+			slog.Error("Error marking user inactive in Redis", "error", err)
+		}
+	}
+
+	slog.Info("Broadcast finished", "sent", stats.sent, "blocked", stats.blocked,
+		"not_started", stats.notStarted, "banned", stats.banned,
+		"opted_out", stats.optedOut, "failed", stats.failed)
+	return stats
+}
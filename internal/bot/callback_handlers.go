@@ -1,84 +1,288 @@
 package bot
 
 import (
+	"context"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"kinopoisk-bot/internal/api"
 	"kinopoisk-bot/internal/model"
 	"log/slog"
 	"strconv"
 	"strings"
 )
 
+// sessionCallbacks lists callbacks that carry a session ID as their first parameter.
+var sessionCallbacks = map[string]bool{
+	"movie_page":         true,
+	"person_page":        true,
+	"person_select":      true,
+	"person_movies_page": true,
+	"related_page":       true,
+	"person_movies_set":  true,
+}
+
 func (b *Bot) handleCallbackQuery(query *tgbotapi.CallbackQuery) {
 	if query.Message == nil {
 		slog.Warn("Received callback without message", "data", query.Data)
 		return
 	}
 
-	callbackConfig := tgbotapi.CallbackConfig{
-		CallbackQueryID: query.ID,
-	}
-	if _, err := b.api.Request(callbackConfig); err != nil {
-		slog.Error("Error sending callback response", "error", err)
-	}
-
 	data := query.Data
 	parts := strings.Split(data, ":")
 	chatID := query.Message.Chat.ID
+	settings := b.userSettings(query.From)
+	lang := settings.Language
 
-	requireSecondParam := map[string]bool{
-		"movie_page":         true,
-		"person_page":        true,
-		"person_select":      true,
-		"person_movies_page": true,
+	if !b.allowCallback(query, lang) {
+		return
 	}
 
-	if requireSecondParam[parts[0]] && len(parts) < 2 {
+	if sessionCallbacks[parts[0]] && len(parts) < 3 {
 		slog.Warn("Invalid callback format", "data", data)
+		b.answerCallback(query, "")
 		return
 	}
 
+	var state *model.SearchState
+	if sessionCallbacks[parts[0]] {
+		var err error
+		state, err = b.redis.GetSession(chatID, parts[1])
+		if err != nil {
+			slog.Error("Error getting session from Redis", "error", err)
+		}
+		if state != nil && !canUseSession(query, state) {
+			b.answerCallbackAlert(query, tr(lang, "session.not_yours"))
+			return
+		}
+		if state == nil {
+			b.answerCallback(query, "")
+			b.sendStateExpired(chatID, lang)
+			return
+		}
+	}
+
+	// Эти обработчики сами отвечают на callback, чтобы показать пользователю результат.
 	switch parts[0] {
+	case "nominate":
+		if len(parts) < 2 {
+			b.answerCallback(query, "")
+			return
+		}
+		movieID, _ := strconv.Atoi(parts[1])
+		b.handleNominate(query, lang, movieID)
+		return
+	case "watchlist_add":
+		if len(parts) < 2 {
+			b.answerCallback(query, "")
+			return
+		}
+		movieID, _ := strconv.Atoi(parts[1])
+		b.handleWatchlistAdd(query, lang, movieID)
+		return
+	}
+
+	b.answerCallback(query, "")
+
+	switch parts[0] {
+	case "menu":
+		if len(parts) < 2 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		b.handleMenu(query, parts[1])
+	case "language":
+		if len(parts) < 2 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		b.handleLanguageSelect(query, parts[1])
+	case "titles":
+		if len(parts) < 2 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		b.handleTitlesSelect(query, lang, parts[1])
+	case "broadcast":
+		if len(parts) < 3 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		b.handleBroadcastAction(query, lang, parts[1], parts[2])
+	case "settings":
+		if len(parts) < 2 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		b.handleSettingsChange(query, parts[1])
 	case "cancel_search":
-		if err := b.redis.DeleteState(chatID); err != nil {
+		if err := b.redis.DeleteState(chatID, query.From.ID); err != nil {
 			slog.Error("Error deleting state from Redis", "error", err)
 		}
-		reply := tgbotapi.NewMessage(chatID, "Поиск отменен")
-		reply.ReplyMarkup = b.createMainMenuKeyboard()
-		_, err := b.api.Send(reply)
+		reply := tgbotapi.NewMessage(chatID, tr(lang, "search.cancelled"))
+		reply.ReplyMarkup = b.createMainMenuKeyboard(lang)
+		_, err := b.sender.Send(reply)
 		if err != nil {
 			slog.Error("Error sending cancel message", "error", err)
 		}
 	case "movie_page":
-		page, _ := strconv.Atoi(parts[1])
-		b.handleMoviePagination(chatID, page)
+		page, _ := strconv.Atoi(parts[2])
+		b.handleMoviePagination(chatID, settings, state, page)
 	case "person_page":
-		page, _ := strconv.Atoi(parts[1])
-		b.handlePersonPagination(chatID, page)
+		page, _ := strconv.Atoi(parts[2])
+		b.handlePersonPagination(chatID, lang, state, page)
 	case "person_select":
+		personID, _ := strconv.Atoi(parts[2])
+		b.handlePersonSelect(chatID, lang, personID)
+	case "person_movies":
+		if len(parts) < 3 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
 		personID, _ := strconv.Atoi(parts[1])
-		b.handlePersonSelect(chatID, personID)
+		role := parts[2]
+		if role == personRoleAll {
+			role = ""
+		}
+		b.handlePersonMovies(chatID, settings, query.From.ID, personID, role)
 	case "person_movies_page":
-		page, _ := strconv.Atoi(parts[1])
-		b.handlePersonMoviesPagination(chatID, page)
+		page, _ := strconv.Atoi(parts[2])
+		b.handlePersonMoviesPagination(chatID, settings, state, page)
+	case "person_movies_set":
+		if len(parts) < 4 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		b.handlePersonMoviesOption(chatID, settings, state, parts[2], parts[3])
+	case "related_page":
+		page, _ := strconv.Atoi(parts[2])
+		b.handleRelatedPagination(chatID, settings, state, page)
+	case "movie":
+		if len(parts) < 2 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		movieID, _ := strconv.Atoi(parts[1])
+		b.handleMovieSelect(chatID, settings, movieID)
+	case "related":
+		if len(parts) < 3 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		movieID, _ := strconv.Atoi(parts[2])
+		b.handleRelatedSelect(chatID, settings, query.From.ID, parts[1], movieID)
+	case "seasons":
+		if len(parts) < 2 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		movieID, _ := strconv.Atoi(parts[1])
+		b.handleSeasons(chatID, lang, editableMessageID(query), movieID)
+	case "season":
+		if len(parts) < 4 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		movieID, _ := strconv.Atoi(parts[1])
+		seasonNumber, _ := strconv.Atoi(parts[2])
+		page, _ := strconv.Atoi(parts[3])
+		b.handleSeasonEpisodes(chatID, lang, editableMessageID(query), movieID, seasonNumber, page)
+	case "trailer":
+		if len(parts) < 2 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		movieID, _ := strconv.Atoi(parts[1])
+		b.handleTrailer(chatID, settings, movieID)
+	case "reviews":
+		if len(parts) < 3 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		movieID, _ := strconv.Atoi(parts[1])
+		page, _ := strconv.Atoi(parts[2])
+		b.handleReviews(chatID, lang, editableMessageID(query), movieID, page)
+	case "review":
+		if len(parts) < 2 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		reviewID, _ := strconv.Atoi(parts[1])
+		b.handleReviewRead(chatID, lang, reviewID)
+	case "watch":
+		if len(parts) < 2 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		movieID, _ := strconv.Atoi(parts[1])
+		showAll := len(parts) > 2 && parts[2] == "all"
+		b.handleWatch(chatID, settings, query.From.ID, editableMessageID(query), movieID, showAll)
+	case "watch_services":
+		if len(parts) < 2 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		movieID, _ := strconv.Atoi(parts[1])
+		b.handleWatchServicesMenu(chatID, lang, query.From.ID, editableMessageID(query), movieID)
+	case "watch_toggle":
+		if len(parts) < 3 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		movieID, _ := strconv.Atoi(parts[1])
+		index, _ := strconv.Atoi(parts[2])
+		b.handleWatchServiceToggle(chatID, lang, query.From.ID, editableMessageID(query), movieID, index)
+	case "random":
+		b.handleRandomCommand(chatID, query.From)
+	case "random_filter":
+		b.handleRandomFilterMenu(chatID, lang, query.From.ID, 0)
+	case "random_set":
+		if len(parts) < 3 {
+			slog.Warn("Invalid callback format", "data", data)
+			return
+		}
+		b.handleRandomFilterSet(query, lang, parts[1], parts[2])
 	}
 }
 
-func (b *Bot) handleMoviePagination(chatID int64, page int) {
-	state, err := b.redis.GetState(chatID)
-	if err != nil {
-		slog.Error("Error getting state in handleMoviePagination", "error", err)
-		b.sendStateExpired(chatID)
-		return
+// canUseSession reports whether the user who pressed a button owns the search
+// it belongs to. In private chats there is only one user, so any press is fine.
+func canUseSession(query *tgbotapi.CallbackQuery, state *model.SearchState) bool {
+	if !isGroupChat(query.Message.Chat) || state.UserID == 0 {
+		return true
+	}
+	return query.From != nil && query.From.ID == state.UserID
+}
+
+// editableMessageID returns the ID of the callback's message when its text can
+// be replaced, or 0 for messages such as photo cards that must stay intact.
+func editableMessageID(query *tgbotapi.CallbackQuery) int {
+	if query.Message.Text == "" {
+		return 0
+	}
+	return query.Message.MessageID
+}
+
+func (b *Bot) answerCallback(query *tgbotapi.CallbackQuery, text string) {
+	if _, err := b.sender.Request(tgbotapi.NewCallback(query.ID, text)); err != nil {
+		slog.Error("Error sending callback response", "error", err)
 	}
-	if state == nil || state.Query == "" {
-		b.sendStateExpired(chatID)
+}
+
+func (b *Bot) answerCallbackAlert(query *tgbotapi.CallbackQuery, text string) {
+	if _, err := b.sender.Request(tgbotapi.NewCallbackWithAlert(query.ID, text)); err != nil {
+		slog.Error("Error sending callback alert", "error", err)
+	}
+}
+
+func (b *Bot) handleMoviePagination(chatID int64, settings model.Settings, state *model.SearchState, page int) {
+	if state.Query == "" {
+		b.sendStateExpired(chatID, settings.Language)
 		return
 	}
 
-	movies, _ := b.kinopoisk.SearchMovie(state.Query, page)
+	movies, _ := b.kinopoisk.SearchMovie(context.Background(), state.Query, page, pageSize(state))
 	if len(movies) == 0 {
-		msg := tgbotapi.NewMessage(chatID, "Больше фильмов не найдено")
-		_, err := b.api.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, tr(settings.Language, "movies.no_more"))
+		_, err := b.sender.Send(msg)
 		if err != nil {
 			slog.Error("Error sending no more movies message", "error", err)
 		}
@@ -86,29 +290,27 @@ func (b *Bot) handleMoviePagination(chatID int64, page int) {
 	}
 
 	state.Page = page
-	if err := b.redis.SaveState(chatID, *state); err != nil {
-		slog.Error("Error saving state to Redis", "error", err)
+	if err := b.redis.SaveSession(chatID, *state); err != nil {
+		slog.Error("Error saving session to Redis", "error", err)
 	}
 
-	b.sendMovies(chatID, movies, page, "movie_page")
+	applyTitleMode(settings.TitleMode, movies)
+	b.sendMovies(chatID, settings, movies, state.SessionID, page, pageSize(state), "movie_page",
+		func(ctx context.Context) ([]model.Movie, error) {
+			return b.kinopoisk.SearchMovie(ctx, state.Query, page+1, pageSize(state))
+		})
 }
 
-func (b *Bot) handlePersonPagination(chatID int64, page int) {
-	state, err := b.redis.GetState(chatID)
-	if err != nil {
-		slog.Error("Error getting state in handlePersonPagination", "error", err)
-		b.sendStateExpired(chatID)
-		return
-	}
-	if state == nil || state.Query == "" {
-		b.sendStateExpired(chatID)
+func (b *Bot) handlePersonPagination(chatID int64, lang string, state *model.SearchState, page int) {
+	if state.Query == "" {
+		b.sendStateExpired(chatID, lang)
 		return
 	}
 
-	persons, _ := b.kinopoisk.SearchPerson(state.Query, page)
+	persons, _ := b.kinopoisk.SearchPerson(state.Query, page, pageSize(state))
 	if len(persons) == 0 {
-		msg := tgbotapi.NewMessage(chatID, "Больше актеров/режиссеров не найдено")
-		_, err := b.api.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, tr(lang, "persons.no_more"))
+		_, err := b.sender.Send(msg)
 		if err != nil {
 			slog.Error("Error sending no more persons message", "error", err)
 		}
@@ -116,45 +318,81 @@ func (b *Bot) handlePersonPagination(chatID int64, page int) {
 	}
 
 	state.Page = page
-	if err := b.redis.SaveState(chatID, *state); err != nil {
-		slog.Error("Error saving state to Redis", "error", err)
+	if err := b.redis.SaveSession(chatID, *state); err != nil {
+		slog.Error("Error saving session to Redis", "error", err)
 	}
 
-	b.sendPersons(chatID, persons, page)
+	b.sendPersons(chatID, lang, persons, state.SessionID, page)
 }
 
-func (b *Bot) handlePersonSelect(chatID int64, personID int) {
-	state := model.SearchState{
+func (b *Bot) handlePersonSelect(chatID int64, lang string, personID int) {
+	person, err := b.kinopoisk.GetPersonByID(personID)
+	if err != nil {
+		b.sendText(chatID, tr(lang, "person.load_failed"))
+		return
+	}
+	b.sendPersonCard(chatID, lang, *person)
+}
+
+func (b *Bot) handlePersonMovies(chatID int64, settings model.Settings, userID int64, personID int, role string) {
+	session, err := b.startSession(chatID, model.SearchState{
 		Type:     searchTypePersonMovies,
 		PersonID: personID,
-		Page:     1,
-	}
-	if err := b.redis.SaveState(chatID, state); err != nil {
-		slog.Error("Error saving person state to Redis", "error", err)
+		Role:     role,
+		Sort:     api.SortByPopularity,
+		UserID:   userID,
+		Limit:    settings.ResultsPerPage,
+	})
+	if err != nil {
+		slog.Error("Error saving person session to Redis", "error", err)
 		return
 	}
 
-	movies, _ := b.kinopoisk.SearchMoviesByPerson(personID, 1)
-	b.sendMovies(chatID, movies, 1, "person_movies_page")
+	movies, _ := b.kinopoisk.SearchMoviesByPerson(context.Background(), personID, role, session.Sort, 1, session.Limit, settings.HideAdult)
+	applyTitleMode(settings.TitleMode, movies)
+	b.sendMovies(chatID, settings, movies, session.SessionID, 1, session.Limit, "person_movies_page",
+		func(ctx context.Context) ([]model.Movie, error) {
+			return b.kinopoisk.SearchMoviesByPerson(ctx, personID, role, session.Sort, 2, session.Limit, settings.HideAdult)
+		},
+		b.createPersonMoviesOptionRows(settings.Language, session)...)
 }
 
-func (b *Bot) handlePersonMoviesPagination(chatID int64, page int) {
-	state, err := b.redis.GetState(chatID)
-	if err != nil {
-		slog.Error("Error getting state in handlePersonMoviesPagination", "error", err)
-		b.sendStateExpired(chatID)
+// handlePersonMoviesOption switches the role or sort order of a filmography
+// and shows it again from the first page.
+func (b *Bot) handlePersonMoviesOption(chatID int64, settings model.Settings, state *model.SearchState, option string, value string) {
+	if state.Type != searchTypePersonMovies {
+		slog.Warn("Invalid state for person movies", "state", state)
+		b.sendStateExpired(chatID, settings.Language)
 		return
 	}
-	if state == nil || state.Type != searchTypePersonMovies {
+
+	switch option {
+	case "role":
+		state.Role = value
+		if value == personRoleAll {
+			state.Role = ""
+		}
+	case "sort":
+		state.Sort = value
+	default:
+		slog.Warn("Unknown person movies option", "option", option)
+		return
+	}
+
+	b.handlePersonMoviesPagination(chatID, settings, state, 1)
+}
+
+func (b *Bot) handlePersonMoviesPagination(chatID int64, settings model.Settings, state *model.SearchState, page int) {
+	if state.Type != searchTypePersonMovies {
 		slog.Warn("Invalid state for person movies", "state", state)
-		b.sendStateExpired(chatID)
+		b.sendStateExpired(chatID, settings.Language)
 		return
 	}
 
-	movies, _ := b.kinopoisk.SearchMoviesByPerson(state.PersonID, page)
+	movies, _ := b.kinopoisk.SearchMoviesByPerson(context.Background(), state.PersonID, state.Role, state.Sort, page, pageSize(state), settings.HideAdult)
 	if len(movies) == 0 {
-		msg := tgbotapi.NewMessage(chatID, "Больше фильмов не найдено")
-		_, err := b.api.Send(msg)
+		msg := tgbotapi.NewMessage(chatID, tr(settings.Language, "movies.no_more"))
+		_, err := b.sender.Send(msg)
 		if err != nil {
 			slog.Error("Error sending no more movies message", "error", err)
 		}
@@ -162,9 +400,14 @@ func (b *Bot) handlePersonMoviesPagination(chatID int64, page int) {
 	}
 
 	state.Page = page
-	if err := b.redis.SaveState(chatID, *state); err != nil {
-		slog.Error("Error saving state to Redis", "error", err)
+	if err := b.redis.SaveSession(chatID, *state); err != nil {
+		slog.Error("Error saving session to Redis", "error", err)
 	}
 
-	b.sendMovies(chatID, movies, page, "person_movies_page")
+	applyTitleMode(settings.TitleMode, movies)
+	b.sendMovies(chatID, settings, movies, state.SessionID, page, pageSize(state), "person_movies_page",
+		func(ctx context.Context) ([]model.Movie, error) {
+			return b.kinopoisk.SearchMoviesByPerson(ctx, state.PersonID, state.Role, state.Sort, page+1, pageSize(state), settings.HideAdult)
+		},
+		b.createPersonMoviesOptionRows(settings.Language, state)...)
 }
@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// chatWorkerIdleTimeout is how long a chat's goroutine waits for more jobs
+	// before it exits.
+	chatWorkerIdleTimeout = time.Minute
+	// chatWorkerQueueSize is how many jobs a chat can have queued before Go
+	// blocks and TryGo gives up.
+	chatWorkerQueueSize = 64
+)
+
+// chatWorkers runs jobs in a goroutine per chat, one at a time in the order
+// they were queued, so a slow chat never holds up the others. A chat's
+// goroutine starts with its first job and exits once the chat goes idle.
+type chatWorkers struct {
+	mu      sync.Mutex
+	workers map[int64]*chatWorker
+}
+
+type chatWorker struct {
+	jobs chan func()
+	// pending counts the queued jobs, the worker only exits at zero
+	pending int
+}
+
+func newChatWorkers() *chatWorkers {
+	return &chatWorkers{workers: make(map[int64]*chatWorker)}
+}
+
+// Go queues job for the chat and returns without waiting for it to run. It
+// only blocks while the chat's queue is full.
+func (w *chatWorkers) Go(chatID int64, job func()) {
+	w.reserve(chatID).jobs <- job
+}
+
+// TryGo is like Go but drops the job and returns false instead of blocking
+// when the chat's queue is full.
+func (w *chatWorkers) TryGo(chatID int64, job func()) bool {
+	worker := w.reserve(chatID)
+	select {
+	case worker.jobs <- job:
+		return true
+	default:
+		w.mu.Lock()
+		worker.pending--
+		w.mu.Unlock()
+		return false
+	}
+}
+
+// reserve returns the chat's worker, starting it if needed, with a job
+// counted as pending so that it doesn't exit before the job is queued.
+func (w *chatWorkers) reserve(chatID int64) *chatWorker {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	worker, ok := w.workers[chatID]
+	if !ok {
+		worker = &chatWorker{jobs: make(chan func(), chatWorkerQueueSize)}
+		w.workers[chatID] = worker
+		go w.run(chatID, worker)
+	}
+	worker.pending++
+	return worker
+}
+
+func (w *chatWorkers) run(chatID int64, worker *chatWorker) {
+	idle := time.NewTimer(chatWorkerIdleTimeout)
+	defer idle.Stop()
+
+	for {
+		select {
+		case job := <-worker.jobs:
+			job()
+			w.mu.Lock()
+			worker.pending--
+			w.mu.Unlock()
+		case <-idle.C:
+			w.mu.Lock()
+			// Задание могли поставить в очередь, пока срабатывал таймер
+			if worker.pending == 0 {
+				delete(w.workers, chatID)
+				w.mu.Unlock()
+				return
+			}
+			w.mu.Unlock()
+		}
+		idle.Reset(chatWorkerIdleTimeout)
+	}
+}
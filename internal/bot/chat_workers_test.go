@@ -0,0 +1,31 @@
+package bot
+
+import "testing"
+
+func TestChatWorkersTryGoDropsWhenFull(t *testing.T) {
+	w := newChatWorkers()
+	release := make(chan struct{})
+	started := make(chan struct{})
+	w.Go(1, func() {
+		close(started)
+		<-release
+	})
+	<-started
+
+	for i := 0; i < chatWorkerQueueSize; i++ {
+		if !w.TryGo(1, func() {}) {
+			t.Fatalf("job %d dropped before the queue was full", i)
+		}
+	}
+	if w.TryGo(1, func() {}) {
+		t.Fatal("job queued past the queue size")
+	}
+
+	// Очередь другого чата не зависит от переполненной
+	done := make(chan struct{})
+	if !w.TryGo(2, func() { close(done) }) {
+		t.Fatal("job for another chat dropped")
+	}
+	<-done
+	close(release)
+}
@@ -0,0 +1,147 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"log/slog"
+	"strconv"
+)
+
+const (
+	collageColumns     = 5
+	collageCellWidth   = 200
+	collageCellHeight  = 300
+	collageGap         = 6
+	collageJPEGQuality = 85
+	badgeDotSize       = 5
+)
+
+var (
+	collageBackground = color.RGBA{R: 24, G: 24, B: 24, A: 255}
+	badgeBackground   = color.RGBA{R: 0, G: 0, B: 0, A: 200}
+)
+
+// digitGlyphs are 3x5 bitmaps of the digits used to number collage cells.
+var digitGlyphs = [10][5]string{
+	{"###", "#.#", "#.#", "#.#", "###"},
+	{".#.", "##.", ".#.", ".#.", "###"},
+	{"###", "..#", "###", "#..", "###"},
+	{"###", "..#", "###", "..#", "###"},
+	{"#.#", "#.#", "###", "..#", "..#"},
+	{"###", "#..", "###", "..#", "###"},
+	{"###", "#..", "###", "#.#", "###"},
+	{"###", "..#", "..#", "..#", "..#"},
+	{"###", "#.#", "###", "#.#", "###"},
+	{"###", "#.#", "###", "..#", "###"},
+}
+
+// buildCollage renders the posters into one numbered grid and returns it as
+// a JPEG. Posters that can't be decoded are replaced with the fallback image.
+func buildCollage(posters []tgbotapi.RequestFileData) ([]byte, error) {
+	if len(posters) == 0 {
+		return nil, fmt.Errorf("no posters")
+	}
+
+	columns := min(len(posters), collageColumns)
+	rows := (len(posters) + columns - 1) / columns
+	width := columns*collageCellWidth + (columns+1)*collageGap
+	height := rows*collageCellHeight + (rows+1)*collageGap
+
+	canvas := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(canvas, canvas.Bounds(), &image.Uniform{C: collageBackground}, image.Point{}, draw.Src)
+
+	for i, poster := range posters {
+		x := collageGap + (i%columns)*(collageCellWidth+collageGap)
+		y := collageGap + (i/columns)*(collageCellHeight+collageGap)
+		cell := image.Rect(x, y, x+collageCellWidth, y+collageCellHeight)
+
+		if img := decodePoster(poster); img != nil {
+			scaleInto(canvas, cell, img)
+		}
+		drawBadge(canvas, cell.Min, i+1)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, canvas, &jpeg.Options{Quality: collageJPEGQuality}); err != nil {
+		return nil, fmt.Errorf("encode collage: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func decodePoster(poster tgbotapi.RequestFileData) image.Image {
+	data := fallbackImage
+	if file, ok := poster.(tgbotapi.FileBytes); ok {
+		data = file.Bytes
+	}
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		slog.Warn("Failed to decode poster for collage", "error", err)
+		return nil
+	}
+	return img
+}
+
+// scaleInto draws src into the dst rectangle, averaging the source pixels
+// that fall into each destination pixel.
+func scaleInto(dst *image.RGBA, rect image.Rectangle, src image.Image) {
+	bounds := src.Bounds()
+	sw, sh := bounds.Dx(), bounds.Dy()
+	dw, dh := rect.Dx(), rect.Dy()
+	if sw == 0 || sh == 0 {
+		return
+	}
+
+	for y := 0; y < dh; y++ {
+		y0 := bounds.Min.Y + y*sh/dh
+		y1 := max(bounds.Min.Y+(y+1)*sh/dh, y0+1)
+		for x := 0; x < dw; x++ {
+			x0 := bounds.Min.X + x*sw/dw
+			x1 := max(bounds.Min.X+(x+1)*sw/dw, x0+1)
+
+			var r, g, b, n uint32
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, _ := src.At(sx, sy).RGBA()
+					r, g, b, n = r+pr, g+pg, b+pb, n+1
+				}
+			}
+			dst.SetRGBA(rect.Min.X+x, rect.Min.Y+y, color.RGBA{
+				R: uint8(r / n >> 8), G: uint8(g / n >> 8), B: uint8(b / n >> 8), A: 255,
+			})
+		}
+	}
+}
+
+// drawBadge draws the cell number in the top left corner of a cell.
+func drawBadge(dst *image.RGBA, at image.Point, number int) {
+	digits := strconv.Itoa(number)
+	padding := badgeDotSize * 2
+	glyphWidth := 3 * badgeDotSize
+	width := len(digits)*glyphWidth + (len(digits)-1)*badgeDotSize + 2*padding
+	height := 5*badgeDotSize + 2*padding
+
+	badge := image.Rect(at.X, at.Y, at.X+width, at.Y+height)
+	draw.Draw(dst, badge, &image.Uniform{C: badgeBackground}, image.Point{}, draw.Over)
+
+	x := at.X + padding
+	for _, digit := range digits {
+		glyph := digitGlyphs[digit-'0']
+		for row, line := range glyph {
+			for col, dot := range line {
+				if dot != '#' {
+					continue
+				}
+				px := x + col*badgeDotSize
+				py := at.Y + padding + row*badgeDotSize
+				draw.Draw(dst, image.Rect(px, py, px+badgeDotSize, py+badgeDotSize),
+					image.White, image.Point{}, draw.Src)
+			}
+		}
+		x += glyphWidth + badgeDotSize
+	}
+}
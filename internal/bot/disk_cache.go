@@ -0,0 +1,246 @@
+package bot
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	diskCacheExt       = ".jpg"
+	diskCacheTmpPrefix = ".tmp-"
+)
+
+// diskCache keeps processed posters on disk between restarts. Files are named
+// by the SHA-256 of the poster URL, and the least recently used ones are
+// evicted once the total size exceeds maxBytes.
+type diskCache struct {
+	dir      string
+	maxBytes int64
+
+	mu      sync.Mutex
+	size    int64
+	order   *list.List // от недавно использованных к давно использованным
+	entries map[string]*list.Element
+}
+
+type diskCacheEntry struct {
+	name string
+	size int64
+}
+
+// posterDiskCache is nil when the disk tier is disabled.
+var posterDiskCache *diskCache
+
+// InitDiskCache enables the on-disk poster cache in dir, capped at maxBytes.
+// Files left by a previous run are picked up in modification time order.
+func InitDiskCache(dir string, maxBytes int64) error {
+	if dir == "" || maxBytes <= 0 {
+		return nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return fmt.Errorf("failed to create disk cache dir: %w", err)
+	}
+
+	cache := &diskCache{
+		dir:      dir,
+		maxBytes: maxBytes,
+		order:    list.New(),
+		entries:  make(map[string]*list.Element),
+	}
+	if err := cache.load(); err != nil {
+		return err
+	}
+	cache.mu.Lock()
+	cache.evict()
+	cache.mu.Unlock()
+
+	posterDiskCache = cache
+	slog.Info("Disk image cache loaded", "dir", dir, "files", cache.order.Len(), "bytes", cache.size)
+	return nil
+}
+
+func (c *diskCache) load() error {
+	files, err := os.ReadDir(c.dir)
+	if err != nil {
+		return fmt.Errorf("failed to read disk cache dir: %w", err)
+	}
+
+	type cachedFile struct {
+		entry   diskCacheEntry
+		modTime time.Time
+	}
+	var cached []cachedFile
+	for _, file := range files {
+		name := file.Name()
+		if file.IsDir() {
+			continue
+		}
+		// Недописанные файлы остаются после падения посреди записи
+		if strings.HasPrefix(name, diskCacheTmpPrefix) {
+			os.Remove(filepath.Join(c.dir, name))
+			continue
+		}
+		if filepath.Ext(name) != diskCacheExt {
+			continue
+		}
+		info, err := file.Info()
+		if err != nil {
+			continue
+		}
+		cached = append(cached, cachedFile{
+			entry:   diskCacheEntry{name: name, size: info.Size()},
+			modTime: info.ModTime(),
+		})
+	}
+
+	sort.Slice(cached, func(i, j int) bool {
+		return cached[i].modTime.After(cached[j].modTime)
+	})
+	for _, file := range cached {
+		c.entries[file.entry.name] = c.order.PushBack(file.entry)
+		c.size += file.entry.size
+	}
+	return nil
+}
+
+func diskCacheName(url string) string {
+	sum := sha256.Sum256([]byte(url))
+	return hex.EncodeToString(sum[:]) + diskCacheExt
+}
+
+// Get returns the cached poster for url and marks it as recently used.
+func (c *diskCache) Get(url string) ([]byte, bool) {
+	name := diskCacheName(url)
+
+	c.mu.Lock()
+	element, ok := c.entries[name]
+	if ok {
+		c.order.MoveToFront(element)
+	}
+	c.mu.Unlock()
+	if !ok {
+		return nil, false
+	}
+
+	path := filepath.Join(c.dir, name)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		slog.Warn("Failed to read cached image", "path", path, "error", err)
+		c.remove(name)
+		return nil, false
+	}
+	// Время изменения хранит порядок LRU между перезапусками
+	now := time.Now()
+	if err := os.Chtimes(path, now, now); err != nil {
+		slog.Debug("Failed to touch cached image", "path", path, "error", err)
+	}
+	return data, true
+}
+
+// Put stores the poster for url. The file is written under a temporary name
+// and renamed into place, so readers never see a partial file.
+func (c *diskCache) Put(url string, data []byte) {
+	if int64(len(data)) > c.maxBytes {
+		return
+	}
+	name := diskCacheName(url)
+
+	if err := c.writeFile(name, data); err != nil {
+		slog.Warn("Failed to write cached image", "name", name, "error", err)
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[name]; ok {
+		c.size -= element.Value.(diskCacheEntry).size
+		c.order.Remove(element)
+	}
+	c.entries[name] = c.order.PushFront(diskCacheEntry{name: name, size: int64(len(data))})
+	c.size += int64(len(data))
+	c.evict()
+}
+
+func (c *diskCache) writeFile(name string, data []byte) error {
+	tmp, err := os.CreateTemp(c.dir, diskCacheTmpPrefix+"*")
+	if err != nil {
+		return fmt.Errorf("create temp file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write temp file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("sync temp file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("close temp file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), filepath.Join(c.dir, name)); err != nil {
+		return fmt.Errorf("rename temp file: %w", err)
+	}
+	return nil
+}
+
+func (c *diskCache) remove(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if element, ok := c.entries[name]; ok {
+		c.size -= element.Value.(diskCacheEntry).size
+		c.order.Remove(element)
+		delete(c.entries, name)
+	}
+}
+
+// evict deletes the least recently used files until the cache fits maxBytes.
+// The caller must hold c.mu.
+func (c *diskCache) evict() {
+	for c.size > c.maxBytes {
+		element := c.order.Back()
+		if element == nil {
+			return
+		}
+		entry := element.Value.(diskCacheEntry)
+		if err := os.Remove(filepath.Join(c.dir, entry.name)); err != nil && !os.IsNotExist(err) {
+			slog.Warn("Failed to evict cached image", "name", entry.name, "error", err)
+		}
+		c.size -= entry.size
+		c.order.Remove(element)
+		delete(c.entries, entry.name)
+	}
+}
+
+// Stats returns the number of cached files and their total size.
+func (c *diskCache) Stats() (files int, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len(), c.size
+}
+
+// Clear deletes all cached files and returns how many there were.
+func (c *diskCache) Clear() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	count := c.order.Len()
+	for name := range c.entries {
+		if err := os.Remove(filepath.Join(c.dir, name)); err != nil && !os.IsNotExist(err) {
+			slog.Warn("Failed to remove cached image", "name", name, "error", err)
+		}
+	}
+	c.order.Init()
+	c.entries = make(map[string]*list.Element)
+	c.size = 0
+	return count
+}
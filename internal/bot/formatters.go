@@ -4,26 +4,209 @@ import (
 	"fmt"
 	"html"
 	"kinopoisk-bot/internal/model"
+	"regexp"
+	"strings"
 )
 
-func formatMovieCaption(movie model.Movie) string {
-	caption := fmt.Sprintf("🎬 %s (%s)\n⭐ %s\n📖 %s",
-		movie.Title, movie.Year, movie.Rating, movie.Description)
-	if len(caption) > telegramCaptionLimit {
-		return caption[:telegramCaptionLimit-3] + "..."
+// personCardFacts limits how many facts go into a person card caption.
+const personCardFacts = 3
+
+var htmlTagPattern = regexp.MustCompile(`<[^>]*>`)
+
+// episodeDescriptionLimit keeps a page of episodes under the message size limit.
+const episodeDescriptionLimit = 300
+
+// seriesStatuses lists the series statuses that have a "series.status.<status>" message.
+var seriesStatuses = map[string]bool{
+	"announced":       true,
+	"pre-production":  true,
+	"filming":         true,
+	"post-production": true,
+	"completed":       true,
+}
+
+func formatMovieCaption(lang string, movie model.Movie) string {
+	caption := fmt.Sprintf("🎬 %s (%s)\n⭐ %s\n", movie.Title, movie.Year, formatRatings(lang, movie))
+	if movie.IsSeries {
+		caption += formatSeriesInfo(lang, movie) + "\n"
+	}
+	if movie.Character != "" {
+		caption += "🎭 " + movie.Character + "\n"
 	}
-	return caption
+	caption += "📖 " + movie.Description
+	return truncateRunes(caption, telegramCaptionLimit)
 }
 
 func formatMovieDescription(movie model.Movie) string {
-	return fmt.Sprintf(
+	description := fmt.Sprintf(
 		`<a href="https://www.kinopoisk.ru/film/%d/">%s (%s)</a>`,
 		movie.Id,
 		html.EscapeString(movie.Title),
 		movie.Year,
 	)
+	if movie.Character != "" {
+		description += " — " + html.EscapeString(movie.Character)
+	}
+	return description
+}
+
+// formatMoviesDescription returns the numbered list of movies that goes along
+// with their posters.
+func formatMoviesDescription(movies []model.Movie) string {
+	var description string
+	for i, movie := range movies {
+		description += fmt.Sprintf("%d. %s\n", i+1, formatMovieDescription(movie))
+	}
+	return description
+}
+
+// formatCompactMovie returns a one-line summary of a movie for the compact
+// results layout.
+func formatCompactMovie(lang string, movie model.Movie) string {
+	line := formatMovieDescription(movie)
+	var ratings []string
+	if movie.Ratings.Kp > 0 {
+		ratings = append(ratings, fmt.Sprintf("%s %.1f", tr(lang, "rating.kp"), movie.Ratings.Kp))
+	}
+	if movie.Ratings.Imdb > 0 {
+		ratings = append(ratings, fmt.Sprintf("%s %.1f", tr(lang, "rating.imdb"), movie.Ratings.Imdb))
+	}
+	if len(ratings) > 0 {
+		line += " ⭐ " + strings.Join(ratings, " · ")
+	}
+	return line
 }
 
 func formatPersonDescription(person model.Person) string {
-	return fmt.Sprintf("%s (%s), %s", person.Name, person.EnName, person.Birth)
+	description := person.Name
+	if description == "" {
+		description = person.EnName
+	} else if person.EnName != "" {
+		description += fmt.Sprintf(" (%s)", person.EnName)
+	}
+	if person.Birth != "" {
+		description += ", " + person.Birth
+	}
+	return description
+}
+
+func formatPersonCard(lang string, person model.Person) string {
+	var lines []string
+	lines = append(lines, "👤 "+formatPersonDescription(person))
+
+	switch {
+	case person.Death != "" && person.Age > 0:
+		lines = append(lines, tr(lang, "person.death_age", person.Death, person.Age))
+	case person.Death != "":
+		lines = append(lines, "✝ "+person.Death)
+	case person.Age > 0:
+		lines = append(lines, tr(lang, "person.age", person.Age))
+	}
+	if person.BirthPlace != "" {
+		lines = append(lines, "📍 "+person.BirthPlace)
+	}
+	if len(person.Professions) > 0 {
+		lines = append(lines, "💼 "+strings.Join(person.Professions, ", "))
+	}
+	if person.Height > 0 {
+		lines = append(lines, tr(lang, "person.height", person.Height))
+	}
+
+	var roles []string
+	for _, role := range personRoles {
+		if count := person.RoleCounts[role]; count > 0 {
+			roles = append(roles, fmt.Sprintf("%s: %d", tr(lang, "role."+role), count))
+		}
+	}
+	if len(roles) > 0 {
+		lines = append(lines, "", strings.Join(roles, "\n"))
+	}
+
+	if len(person.Facts) > 0 {
+		lines = append(lines, "", tr(lang, "person.facts"))
+		for i, fact := range person.Facts {
+			if i == personCardFacts {
+				break
+			}
+			lines = append(lines, "• "+html.UnescapeString(htmlTagPattern.ReplaceAllString(fact, "")))
+		}
+	}
+
+	return truncateRunes(strings.Join(lines, "\n"), telegramCaptionLimit)
+}
+
+func formatSeriesInfo(lang string, movie model.Movie) string {
+	info := tr(lang, "series")
+	if movie.SeriesLength > 0 {
+		info += ", " + tr(lang, "series.length", movie.SeriesLength)
+	}
+	if seriesStatuses[movie.Status] {
+		info += ", " + tr(lang, "series.status."+movie.Status)
+	}
+	return info
+}
+
+func formatSeason(lang string, season model.Season, episodes []model.Episode, page int, pages int) string {
+	title := tr(lang, "season.title", season.Number)
+	if season.Name != "" {
+		title += ": " + season.Name
+	}
+
+	text := fmt.Sprintf("<b>📺 %s</b> (%s)\n", html.EscapeString(title), tr(lang, "pagination.short", page, pages))
+	if len(episodes) == 0 {
+		return text + "\n" + tr(lang, "season.no_episodes")
+	}
+	for _, episode := range episodes {
+		text += fmt.Sprintf("\n<b>%d. %s</b>", episode.Number, html.EscapeString(episode.Name))
+		if episode.AirDate != "" {
+			text += " — " + episode.AirDate
+		}
+		text += "\n"
+		if episode.Description != "" {
+			text += html.EscapeString(truncateRunes(episode.Description, episodeDescriptionLimit)) + "\n"
+		}
+	}
+	return text
+}
+
+// formatRatings lists every available rating with its vote count.
+func formatRatings(lang string, movie model.Movie) string {
+	r := movie.Ratings
+	var parts []string
+	if r.Kp > 0 {
+		parts = append(parts, tr(lang, "rating.kp")+" "+formatScore(r.Kp, r.KpVotes))
+	}
+	if r.Imdb > 0 {
+		parts = append(parts, tr(lang, "rating.imdb")+" "+formatScore(r.Imdb, r.ImdbVotes))
+	}
+	if r.FilmCritics > 0 {
+		parts = append(parts, tr(lang, "rating.critics")+" "+formatScore(r.FilmCritics, r.FilmCriticsVotes))
+	}
+	if r.RussianFilmCritics > 0 {
+		parts = append(parts, tr(lang, "rating.russian_critics", r.RussianFilmCritics)+formatVotes(r.RussianFilmCriticsVotes))
+	}
+	if r.Await > 0 {
+		parts = append(parts, tr(lang, "rating.await", r.Await)+formatVotes(r.AwaitVotes))
+	}
+	if len(parts) == 0 {
+		return movie.Rating
+	}
+	return strings.Join(parts, " · ")
+}
+
+func formatScore(score float64, votes int) string {
+	return fmt.Sprintf("%.1f", score) + formatVotes(votes)
+}
+
+func formatVotes(votes int) string {
+	switch {
+	case votes >= 1_000_000:
+		return fmt.Sprintf(" (%.1fM)", float64(votes)/1_000_000)
+	case votes >= 1_000:
+		return fmt.Sprintf(" (%.1fK)", float64(votes)/1_000)
+	case votes > 0:
+		return fmt.Sprintf(" (%d)", votes)
+	default:
+		return ""
+	}
 }
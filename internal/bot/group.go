@@ -0,0 +1,78 @@
+package bot
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"strings"
+)
+
+func isGroupChat(chat *tgbotapi.Chat) bool {
+	return chat != nil && (chat.IsGroup() || chat.IsSuperGroup())
+}
+
+// isCommandForBot reports whether a command may be handled by this bot.
+// Commands explicitly addressed to another bot (/search@other_bot) are ignored.
+func (b *Bot) isCommandForBot(msg *tgbotapi.Message) bool {
+	_, botName, found := strings.Cut(msg.CommandWithAt(), "@")
+	return !found || strings.EqualFold(botName, b.api.Self.UserName)
+}
+
+// isAddressedToBot reports whether a plain group message is meant for the bot:
+// a reply to one of its messages or a mention.
+func (b *Bot) isAddressedToBot(msg *tgbotapi.Message) bool {
+	if msg.ReplyToMessage != nil && msg.ReplyToMessage.From != nil &&
+		msg.ReplyToMessage.From.ID == b.api.Self.ID {
+		return true
+	}
+	return b.mentionsBot(msg)
+}
+
+func (b *Bot) mentionsBot(msg *tgbotapi.Message) bool {
+	mention := "@" + b.api.Self.UserName
+	for _, entity := range msg.Entities {
+		if entity.Type != "mention" {
+			continue
+		}
+		if strings.EqualFold(entityText(msg.Text, entity), mention) {
+			return true
+		}
+	}
+	return false
+}
+
+// messageQuery returns the message text with mentions of the bot removed.
+func (b *Bot) messageQuery(msg *tgbotapi.Message) string {
+	text := msg.Text
+	mention := "@" + b.api.Self.UserName
+	for i := len(msg.Entities) - 1; i >= 0; i-- {
+		entity := msg.Entities[i]
+		if entity.Type != "mention" || !strings.EqualFold(entityText(text, entity), mention) {
+			continue
+		}
+		runes := []rune(text)
+		start, end := utf16Offset(runes, entity.Offset), utf16Offset(runes, entity.Offset+entity.Length)
+		text = string(runes[:start]) + string(runes[end:])
+	}
+	return strings.TrimSpace(text)
+}
+
+// entityText extracts the text of an entity. Telegram counts entity offsets in
+// UTF-16 code units, so they can't be used as byte or rune indexes directly.
+func entityText(text string, entity tgbotapi.MessageEntity) string {
+	runes := []rune(text)
+	start, end := utf16Offset(runes, entity.Offset), utf16Offset(runes, entity.Offset+entity.Length)
+	return string(runes[start:end])
+}
+
+func utf16Offset(runes []rune, units int) int {
+	count := 0
+	for i, r := range runes {
+		if count >= units {
+			return i
+		}
+		count++
+		if r > 0xFFFF {
+			count++
+		}
+	}
+	return len(runes)
+}
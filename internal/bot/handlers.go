@@ -1,128 +1,180 @@
 package bot
 
 import (
+	"context"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 	"kinopoisk-bot/internal/model"
 	"log/slog"
 )
 
 func (b *Bot) handleStartCommand(msg *tgbotapi.Message) {
-	text := "Привет! Я бот для поиска фильмов и актеров/режиссеров в Кинопоиске.\n\n" +
-		"Выберите тип поиска:"
-	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
-	reply.ReplyMarkup = b.createMainMenuKeyboard()
-	_, err := b.api.Send(reply)
+	lang := b.userLang(msg.From)
+	// Из личного чата пользователь уже зарегистрирован в handleUpdate
+	if !msg.Chat.IsPrivate() {
+		b.registerUser(msg.From)
+	}
+
+	// Убираем клавиатуру со старым текстовым меню, если она осталась у пользователя.
+	greeting := tgbotapi.NewMessage(msg.Chat.ID, tr(lang, "start.greeting"))
+	greeting.ReplyMarkup = tgbotapi.NewRemoveKeyboard(true)
+	if _, err := b.sender.Send(greeting); err != nil {
+		slog.Error("Error sending message in handleStartCommand", "error", err)
+	}
+
+	reply := tgbotapi.NewMessage(msg.Chat.ID, tr(lang, "start.choose"))
+	reply.ReplyMarkup = b.createMainMenuKeyboard(lang)
+	_, err := b.sender.Send(reply)
 	if err != nil {
-		slog.Error("Error sending message in handleStartCommand", err)
+		slog.Error("Error sending message in handleStartCommand", "error", err)
 	}
 }
 
 func (b *Bot) handleHelpCommand(msg *tgbotapi.Message) {
-	text := "Как использовать бота:\n\n" +
-		"1. Выберите тип поиска\n" +
-		"2. Введите запрос для поиска\n\n" +
-		"Доступные команды:\n" +
-		"/start - начать работу\n" +
-		"/help - показать справку"
-	reply := tgbotapi.NewMessage(msg.Chat.ID, text)
-	reply.ReplyMarkup = b.createMainMenuKeyboard()
-	_, err := b.api.Send(reply)
+	lang := b.userLang(msg.From)
+	reply := tgbotapi.NewMessage(msg.Chat.ID, tr(lang, "help"))
+	reply.ReplyMarkup = b.createMainMenuKeyboard(lang)
+	_, err := b.sender.Send(reply)
 	if err != nil {
-		slog.Error("Error sending message in handleHelpCommand", err)
+		slog.Error("Error sending message in handleHelpCommand", "error", err)
+	}
+}
+
+func (b *Bot) handleSearchCommand(msg *tgbotapi.Message, searchType string) {
+	query := msg.CommandArguments()
+	if query == "" {
+		b.awaitingQuery(msg.Chat, msg.From, msg.MessageID, searchType)
+		return
 	}
+	b.runSearch(msg.Chat.ID, msg.From, searchType, query)
 }
 
-func (b *Bot) handleMessage(msg *tgbotapi.Message) {
-	switch msg.Text {
-	case "🎬 Поиск фильмов":
-		b.awaitingQuery(msg.Chat.ID, searchTypeMovie)
-	case "👤 Поиск актеров/режиссеров":
-		b.awaitingQuery(msg.Chat.ID, searchTypePerson)
+// handleMenu handles presses of the main menu buttons.
+func (b *Bot) handleMenu(query *tgbotapi.CallbackQuery, item string) {
+	switch item {
+	case searchTypeMovie, searchTypePerson:
+		b.awaitingQuery(query.Message.Chat, query.From, 0, item)
+	case menuRandom:
+		b.handleRandomCommand(query.Message.Chat.ID, query.From)
 	default:
-		b.processSearchQuery(msg)
+		slog.Warn("Unknown menu item", "item", item)
 	}
 }
 
-func (b *Bot) awaitingQuery(chatID int64, searchType string) {
+// awaitingQuery asks the user for a search query. replyTo is the message the
+// prompt answers in a group, or 0 when there is none.
+func (b *Bot) awaitingQuery(chat *tgbotapi.Chat, user *tgbotapi.User, replyTo int, searchType string) {
+	lang := b.userLang(user)
+
 	// Сохраняем тип поиска в Redis
 	state := model.SearchState{Type: searchType}
-	if err := b.redis.SaveState(chatID, state); err != nil {
-		slog.Error("Error saving state to Redis", err)
+	if err := b.redis.SaveState(chat.ID, user.ID, state); err != nil {
+		slog.Error("Error saving state to Redis", "error", err)
 		return
 	}
 
-	message := "Введите название фильма для поиска:"
+	message := tr(lang, "search.prompt.movie")
 	if searchType == searchTypePerson {
-		message = "Введите имя актера или режиссера для поиска:"
+		message = tr(lang, "search.prompt.person")
 	}
 
-	reply := tgbotapi.NewMessage(chatID, message)
-	reply.ReplyMarkup = b.createCancelKeyboard()
-	_, err := b.api.Send(reply)
+	reply := tgbotapi.NewMessage(chat.ID, message)
+	if isGroupChat(chat) {
+		// В группе бот видит только ответы на свои сообщения,
+		// поэтому просим ответить именно на этот запрос.
+		reply.ReplyToMessageID = replyTo
+		reply.ReplyMarkup = tgbotapi.ForceReply{ForceReply: true, Selective: replyTo != 0}
+	} else {
+		reply.ReplyMarkup = b.createCancelKeyboard(lang)
+	}
+	_, err := b.sender.Send(reply)
 	if err != nil {
 		slog.Error("Error sending message", "type", searchType, "error", err)
 	}
 }
 
 func (b *Bot) processSearchQuery(msg *tgbotapi.Message) {
-	state, err := b.redis.GetState(msg.Chat.ID)
+	lang := b.userLang(msg.From)
+	state, err := b.redis.GetState(msg.Chat.ID, msg.From.ID)
 	if err != nil {
 		slog.Error("Error getting state from Redis", "error", err)
 		return
 	}
 
+	query := b.messageQuery(msg)
+	if state == nil && isGroupChat(msg.Chat) && query != "" {
+		// Упоминание бота без выбранного типа поиска ищет фильмы.
+		state = &model.SearchState{Type: searchTypeMovie}
+	}
+
 	if state == nil {
-		reply := tgbotapi.NewMessage(msg.Chat.ID, "Пожалуйста, выберите тип поиска с помощью кнопок ниже 👇")
-		reply.ReplyMarkup = b.createMainMenuKeyboard()
-		_, err := b.api.Send(reply)
+		reply := tgbotapi.NewMessage(msg.Chat.ID, tr(lang, "search.choose_type"))
+		reply.ReplyMarkup = b.createMainMenuKeyboard(lang)
+		_, err := b.sender.Send(reply)
 		if err != nil {
-			slog.Error("Error sending choose search type message", err)
+			slog.Error("Error sending choose search type message", "error", err)
 		}
 		return
 	}
 
-	query := msg.Text
 	if query == "" {
-		reply := tgbotapi.NewMessage(msg.Chat.ID, "Пожалуйста, укажите запрос для поиска")
-		reply.ReplyMarkup = b.createMainMenuKeyboard()
-		_, err := b.api.Send(reply)
+		reply := tgbotapi.NewMessage(msg.Chat.ID, tr(lang, "search.empty_query"))
+		reply.ReplyMarkup = b.createMainMenuKeyboard(lang)
+		_, err := b.sender.Send(reply)
 		if err != nil {
-			slog.Error("Error sending empty query message", err)
+			slog.Error("Error sending empty query message", "error", err)
 		}
 		return
 	}
 
-	state.Query = query
-	state.Page = 1
-	if err := b.redis.SaveState(msg.Chat.ID, *state); err != nil {
-		slog.Error("Error saving state to Redis", err)
+	b.runSearch(msg.Chat.ID, msg.From, state.Type, query)
+}
+
+func (b *Bot) runSearch(chatID int64, user *tgbotapi.User, searchType string, query string) {
+	settings := b.userSettings(user)
+	lang := settings.Language
+	if !b.allowSearch(user) {
+		b.sendText(chatID, tr(lang, "rate_limit.search"))
+		return
+	}
+	session, err := b.startSession(chatID, model.SearchState{
+		Type:   searchType,
+		Query:  query,
+		UserID: user.ID,
+		Limit:  settings.ResultsPerPage,
+	})
+	if err != nil {
+		slog.Error("Error saving session to Redis", "error", err)
 		return
 	}
 
-	switch state.Type {
+	switch session.Type {
 	case searchTypeMovie:
-		movies, _ := b.kinopoisk.SearchMovie(query, 1)
+		movies, _ := b.kinopoisk.SearchMovie(context.Background(), query, 1, session.Limit)
 		if len(movies) == 0 {
-			reply := tgbotapi.NewMessage(msg.Chat.ID, "Фильмы не найдены")
-			reply.ReplyMarkup = b.createMainMenuKeyboard()
-			_, err := b.api.Send(reply)
+			reply := tgbotapi.NewMessage(chatID, tr(lang, "movies.not_found"))
+			reply.ReplyMarkup = b.createMainMenuKeyboard(lang)
+			_, err := b.sender.Send(reply)
 			if err != nil {
-				slog.Error("Error sending no movies message", err)
+				slog.Error("Error sending no movies message", "error", err)
 			}
 			return
 		}
-		b.sendMovies(msg.Chat.ID, movies, 1, "movie_page")
+		applyTitleMode(settings.TitleMode, movies)
+		b.sendMovies(chatID, settings, movies, session.SessionID, 1, session.Limit, "movie_page",
+			func(ctx context.Context) ([]model.Movie, error) {
+				return b.kinopoisk.SearchMovie(ctx, query, 2, session.Limit)
+			})
 	case searchTypePerson:
-		persons, _ := b.kinopoisk.SearchPerson(query, 1)
+		persons, _ := b.kinopoisk.SearchPerson(query, 1, session.Limit)
 		if len(persons) == 0 {
-			reply := tgbotapi.NewMessage(msg.Chat.ID, "Актеры/режиссеры не найдены")
-			reply.ReplyMarkup = b.createMainMenuKeyboard()
-			_, err := b.api.Send(reply)
+			reply := tgbotapi.NewMessage(chatID, tr(lang, "persons.not_found"))
+			reply.ReplyMarkup = b.createMainMenuKeyboard(lang)
+			_, err := b.sender.Send(reply)
 			if err != nil {
-				slog.Error("Error sending no persons message", err)
+				slog.Error("Error sending no persons message", "error", err)
 			}
 			return
 		}
-		b.sendPersons(msg.Chat.ID, persons, 1)
+		b.sendPersons(chatID, lang, persons, session.SessionID, 1)
 	}
 }
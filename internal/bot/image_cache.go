@@ -5,24 +5,26 @@ import (
 	"context"
 	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"golang.org/x/sync/singleflight"
 	"image"
 	_ "image/jpeg"
 	_ "image/png"
 	"io"
 	"log/slog"
 	"net/http"
+	neturl "net/url"
 	"os"
-	"strings"
 	"sync"
 	"time"
 )
 
 var (
 	imageCache     sync.Map
+	posterRequests singleflight.Group
 	fallbackImage  []byte
 	fallbackLoaded bool
 	fallbackMutex  sync.Mutex
-	httpClient     = &http.Client{Timeout: 10 * time.Second}
+	httpClient     = newImageHTTPClient()
 	validMimeTypes = map[string]bool{
 		"image/jpeg": true,
 		"image/png":  true,
@@ -76,21 +78,49 @@ func GetSafePoster(url string) tgbotapi.RequestFileData {
 		}
 	}
 
-	imgData, contentType, err := downloadAndValidateImage(url)
+	// Одновременные запросы одного постера ждут общую загрузку
+	data, err, _ := posterRequests.Do(url, func() (interface{}, error) {
+		return loadPoster(url)
+	})
 	if err != nil {
-		slog.Warn("Failed to download image", "url", url, "error", err)
-		imageCache.Store(url, err)
 		return getFallbackImageReader()
 	}
+	return tgbotapi.FileBytes{
+		Name:  "poster.jpg",
+		Bytes: data.([]byte),
+	}
+}
+
+// loadPoster returns the processed poster from the disk cache or downloads
+// it, and fills the memory cache with the result.
+func loadPoster(url string) ([]byte, error) {
+	if posterDiskCache != nil {
+		if data, ok := posterDiskCache.Get(url); ok {
+			imageCache.Store(url, data)
+			return data, nil
+		}
+	}
 
-	ext := getExtensionFromContentType(contentType)
+	imgData, _, err := downloadAndValidateImage(url)
+	if err != nil {
+		slog.Warn("Failed to download image", "url", url, "error", err)
+		imageCache.Store(url, err)
+		return nil, err
+	}
 
-	imageCache.Store(url, imgData)
+	// В кэш кладем уже обработанный постер, чтобы не пережимать его повторно
+	processed, err := processPoster(imgData)
+	if err != nil {
+		slog.Warn("Failed to process image", "url", url, "error", err)
+		imageCache.Store(url, err)
+		return nil, err
+	}
 
-	return tgbotapi.FileBytes{
-		Name:  "poster" + ext,
-		Bytes: imgData,
+	imageCache.Store(url, processed)
+	if posterDiskCache != nil {
+		posterDiskCache.Put(url, processed)
 	}
+	return processed, nil
 }
 
 func downloadAndValidateImage(url string) ([]byte, string, error) {
@@ -99,6 +129,17 @@ func downloadAndValidateImage(url string) ([]byte, string, error) {
 	//	slog.Debug("Execution time", "duration", time.Since(start))
 	//}()
 
+	parsed, err := neturl.Parse(url)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid url: %w", err)
+	}
+	if err := checkPosterURL(parsed); err != nil {
+		return nil, "", err
+	}
+
+	release := acquireImageFetch()
+	defer release()
+
 	resp, err := httpClient.Get(url)
 	if err != nil {
 		return nil, "", fmt.Errorf("http get failed: %w", err)
@@ -109,10 +150,17 @@ func downloadAndValidateImage(url string) ([]byte, string, error) {
 		return nil, "", fmt.Errorf("invalid status code: %d", resp.StatusCode)
 	}
 
-	imgData, err := io.ReadAll(io.LimitReader(resp.Body, 5<<20))
+	if resp.ContentLength > posterMaxBytes {
+		return nil, "", fmt.Errorf("image too large: %d bytes", resp.ContentLength)
+	}
+	// Читаем на байт больше лимита, чтобы отличить слишком большой файл от обрезанного
+	imgData, err := io.ReadAll(io.LimitReader(resp.Body, posterMaxBytes+1))
 	if err != nil {
 		return nil, "", fmt.Errorf("read failed: %w", err)
 	}
+	if len(imgData) > posterMaxBytes {
+		return nil, "", fmt.Errorf("image too large: over %d bytes", posterMaxBytes)
+	}
 
 	contentType := resp.Header.Get("Content-Type")
 	if contentType == "" {
@@ -127,29 +175,17 @@ func downloadAndValidateImage(url string) ([]byte, string, error) {
 		return nil, "", fmt.Errorf("image too small: %d bytes", len(imgData))
 	}
 
-	_, _, err = image.DecodeConfig(bytes.NewReader(imgData))
+	config, _, err := image.DecodeConfig(bytes.NewReader(imgData))
 	if err != nil {
 		return nil, "", fmt.Errorf("invalid image format: %w", err)
 	}
+	if err := checkPosterDimensions(config); err != nil {
+		return nil, "", err
+	}
 
 	return imgData, contentType, nil
 }
 
-func getExtensionFromContentType(contentType string) string {
-	switch {
-	case strings.Contains(contentType, "jpeg"):
-		return ".jpg"
-	case strings.Contains(contentType, "png"):
-		return ".png"
-	case strings.Contains(contentType, "gif"):
-		return ".gif"
-	case strings.Contains(contentType, "webp"):
-		return ".webp"
-	default:
-		return ".jpg"
-	}
-}
-
 func getFallbackImageReader() tgbotapi.RequestFileData {
 	if !fallbackLoaded {
 		if err := InitImageCache(); err != nil {
@@ -170,15 +206,34 @@ func ClearImageCachePeriodically(ctx context.Context, interval time.Duration) {
 	for {
 		select {
 		case <-ticker.C:
-			clearCount := 0
-			imageCache.Range(func(key, value interface{}) bool {
-				imageCache.Delete(key)
-				clearCount++
-				return true
-			})
-			slog.Info("Image cache cleared", "count", clearCount)
+			slog.Info("Image cache cleared", "count", clearImageCache())
 		case <-ctx.Done():
 			return
 		}
 	}
 }
+
+// clearImageCache empties the in-memory poster cache and returns the number
+// of dropped entries.
+func clearImageCache() int {
+	clearCount := 0
+	imageCache.Range(func(key, value interface{}) bool {
+		imageCache.Delete(key)
+		clearCount++
+		return true
+	})
+	return clearCount
+}
+
+// imageCacheStats returns the number of posters in the in-memory cache and
+// their total size. Cached download errors count as entries without size.
+func imageCacheStats() (entries int, size int64) {
+	imageCache.Range(func(key, value interface{}) bool {
+		entries++
+		if data, ok := value.([]byte); ok {
+			size += int64(len(data))
+		}
+		return true
+	})
+	return entries, size
+}
@@ -0,0 +1,151 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+)
+
+const (
+	// imageFetchConcurrency caps simultaneous poster downloads across all chats.
+	imageFetchConcurrency = 8
+	imageMaxRedirects     = 3
+)
+
+// defaultPosterHosts are the CDNs kinopoisk.dev serves posters from.
+// Subdomains of the listed hosts are allowed too.
+var defaultPosterHosts = []string{
+	"image.openmoviedb.com",
+	"avatars.mds.yandex.net",
+	"st.kp.yandex.net",
+	"kinopoisk-ru.clstorage.net",
+	"image.tmdb.org",
+	"imagetmdb.com",
+}
+
+// nonPublicPrefixes are the special-purpose ranges from the IANA registries
+// that posters must never be fetched from.
+var nonPublicPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),       // текущая сеть
+	netip.MustParsePrefix("10.0.0.0/8"),      // частная сеть
+	netip.MustParsePrefix("100.64.0.0/10"),   // CGNAT
+	netip.MustParsePrefix("127.0.0.0/8"),     // loopback
+	netip.MustParsePrefix("169.254.0.0/16"),  // link-local
+	netip.MustParsePrefix("172.16.0.0/12"),   // частная сеть
+	netip.MustParsePrefix("192.0.0.0/24"),    // протокольные назначения IETF
+	netip.MustParsePrefix("192.0.2.0/24"),    // документация
+	netip.MustParsePrefix("192.88.99.0/24"),  // 6to4 relay
+	netip.MustParsePrefix("192.168.0.0/16"),  // частная сеть
+	netip.MustParsePrefix("198.18.0.0/15"),   // тестирование производительности
+	netip.MustParsePrefix("198.51.100.0/24"), // документация
+	netip.MustParsePrefix("203.0.113.0/24"),  // документация
+	netip.MustParsePrefix("224.0.0.0/4"),     // multicast
+	netip.MustParsePrefix("240.0.0.0/4"),     // зарезервировано и broadcast
+	netip.MustParsePrefix("::/128"),          // unspecified
+	netip.MustParsePrefix("::1/128"),         // loopback
+	netip.MustParsePrefix("::ffff:0:0/96"),   // IPv4-mapped
+	netip.MustParsePrefix("64:ff9b::/96"),    // NAT64
+	netip.MustParsePrefix("64:ff9b:1::/48"),  // локальный NAT64
+	netip.MustParsePrefix("100::/64"),        // discard
+	netip.MustParsePrefix("2001::/23"),       // протокольные назначения IETF
+	netip.MustParsePrefix("2001:db8::/32"),   // документация
+	netip.MustParsePrefix("2002::/16"),       // 6to4
+	netip.MustParsePrefix("fc00::/7"),        // unique local
+	netip.MustParsePrefix("fe80::/10"),       // link-local
+	netip.MustParsePrefix("ff00::/8"),        // multicast
+}
+
+var (
+	posterHosts    atomic.Pointer[[]string]
+	imageFetchSlot = make(chan struct{}, imageFetchConcurrency)
+
+	errHostNotAllowed   = errors.New("host is not allowed")
+	errAddressForbidden = errors.New("address is forbidden")
+)
+
+func init() {
+	posterHosts.Store(&defaultPosterHosts)
+}
+
+// SetPosterHosts replaces the allowlist of poster hosts. An empty list resets
+// the defaults.
+func SetPosterHosts(hosts []string) {
+	if len(hosts) == 0 {
+		hosts = defaultPosterHosts
+	}
+	posterHosts.Store(&hosts)
+}
+
+// newImageHTTPClient returns the client posters are downloaded with. It only
+// connects to public addresses and follows a few redirects to allowed hosts.
+func newImageHTTPClient() *http.Client {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		// Проверяем уже разрешенный адрес, чтобы DNS не мог увести запрос во внутреннюю сеть
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			if ip, err := netip.ParseAddr(host); err != nil || !isPublicIP(ip) {
+				return fmt.Errorf("%w: %s", errAddressForbidden, host)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+
+	return &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= imageMaxRedirects {
+				return fmt.Errorf("stopped after %d redirects", imageMaxRedirects)
+			}
+			return checkPosterURL(req.URL)
+		},
+	}
+}
+
+// checkPosterURL rejects URLs that don't point to an allowed poster host.
+func checkPosterURL(u *url.URL) error {
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return fmt.Errorf("unsupported scheme: %q", u.Scheme)
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, allowed := range *posterHosts.Load() {
+		if host == allowed || strings.HasSuffix(host, "."+allowed) {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: %s", errHostNotAllowed, host)
+}
+
+// isPublicIP reports whether ip is outside all non-public ranges. IPv4
+// addresses mapped into IPv6 are checked as IPv4.
+func isPublicIP(ip netip.Addr) bool {
+	ip = ip.Unmap().WithZone("")
+	for _, prefix := range nonPublicPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return ip.IsValid()
+}
+
+// acquireImageFetch waits for a free download slot. The returned function
+// releases it.
+func acquireImageFetch() func() {
+	imageFetchSlot <- struct{}{}
+	return func() { <-imageFetchSlot }
+}
@@ -0,0 +1,152 @@
+package bot
+
+import (
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"net/url"
+	"testing"
+)
+
+func TestCheckPosterURL(t *testing.T) {
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://image.openmoviedb.com/kinopoisk-images/1/poster.jpg", true},
+		{"http://image.openmoviedb.com/poster.jpg", true},
+		{"https://IMAGE.OpenMovieDB.com/poster.jpg", true},
+		{"https://cdn.image.openmoviedb.com/poster.jpg", true},
+		{"https://image.openmoviedb.com:8443/poster.jpg", true},
+		{"https://avatars.mds.yandex.net/get-kinopoisk-image/1/orig", true},
+		{"https://evilimage.openmoviedb.com/poster.jpg", false},
+		{"https://image.openmoviedb.com.evil.com/poster.jpg", false},
+		{"https://openmoviedb.com/poster.jpg", false},
+		{"https://evil.com/image.openmoviedb.com/poster.jpg", false},
+		{"https://evil.com/?host=image.openmoviedb.com", false},
+		{"https://image.openmoviedb.com@evil.com/poster.jpg", false},
+		{"ftp://image.openmoviedb.com/poster.jpg", false},
+		{"file:///etc/passwd", false},
+		{"https://127.0.0.1/poster.jpg", false},
+		{"https://169.254.169.254/latest/meta-data/", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.url, func(t *testing.T) {
+			u, err := url.Parse(tt.url)
+			if err != nil {
+				t.Fatalf("parse %q: %v", tt.url, err)
+			}
+			if err := checkPosterURL(u); (err == nil) != tt.allowed {
+				t.Errorf("checkPosterURL(%q) = %v, want allowed %v", tt.url, err, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := []struct {
+		ip     string
+		public bool
+	}{
+		{"93.158.134.3", true},
+		{"8.8.8.8", true},
+		{"2a02:6b8::2:242", true},
+		{"0.0.0.0", false},
+		{"0.1.2.3", false},
+		{"10.1.2.3", false},
+		{"100.64.0.1", false},
+		{"100.127.255.254", false},
+		{"127.0.0.1", false},
+		{"169.254.169.254", false},
+		{"172.16.0.1", false},
+		{"172.31.255.255", false},
+		{"192.0.0.8", false},
+		{"192.0.2.1", false},
+		{"192.168.1.1", false},
+		{"198.18.0.1", false},
+		{"198.19.255.255", false},
+		{"224.0.0.1", false},
+		{"255.255.255.255", false},
+		{"::", false},
+		{"::1", false},
+		{"::ffff:127.0.0.1", false},
+		{"::ffff:10.0.0.1", false},
+		{"64:ff9b::a00:1", false},
+		{"fc00::1", false},
+		{"fd12:3456::1", false},
+		{"fe80::1", false},
+		{"fe80::1%eth0", false},
+		{"ff02::1", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ip, func(t *testing.T) {
+			ip := netip.MustParseAddr(tt.ip)
+			if got := isPublicIP(ip); got != tt.public {
+				t.Errorf("isPublicIP(%s) = %v, want %v", tt.ip, got, tt.public)
+			}
+		})
+	}
+}
+
+func TestImageHTTPClientRedirects(t *testing.T) {
+	client := newImageHTTPClient()
+	via := []*http.Request{httptest.NewRequest(http.MethodGet, "https://image.openmoviedb.com/poster.jpg", nil)}
+
+	tests := []struct {
+		name    string
+		target  string
+		via     int
+		allowed bool
+	}{
+		{"allowed host", "https://st.kp.yandex.net/poster.jpg", 1, true},
+		{"host not allowed", "https://evil.com/poster.jpg", 1, false},
+		{"suffix without dot", "https://evilimage.openmoviedb.com/poster.jpg", 1, false},
+		{"internal address", "http://169.254.169.254/latest/meta-data/", 1, false},
+		{"too many redirects", "https://image.openmoviedb.com/poster.jpg", imageMaxRedirects, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, tt.target, nil)
+			history := make([]*http.Request, tt.via)
+			for i := range history {
+				history[i] = via[0]
+			}
+			if err := client.CheckRedirect(req, history); (err == nil) != tt.allowed {
+				t.Errorf("CheckRedirect(%s) = %v, want allowed %v", tt.target, err, tt.allowed)
+			}
+		})
+	}
+}
+
+func TestImageHTTPClientRefusesPrivateAddresses(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	_, err := newImageHTTPClient().Get(server.URL)
+	if !errors.Is(err, errAddressForbidden) {
+		t.Fatalf("Get(%s) error = %v, want %v", server.URL, err, errAddressForbidden)
+	}
+}
+
+func TestImageHTTPClientRefusesRedirectToHostNotAllowed(t *testing.T) {
+	previous := posterHosts.Load()
+	SetPosterHosts([]string{"127.0.0.1"})
+	defer posterHosts.Store(previous)
+
+	// Клиент с той же проверкой редиректов, но без запрета локальных адресов
+	client := &http.Client{CheckRedirect: newImageHTTPClient().CheckRedirect}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, port, _ := net.SplitHostPort(r.Host)
+		http.Redirect(w, r, "http://localhost:"+port+"/poster.jpg", http.StatusFound)
+	}))
+	defer server.Close()
+
+	_, err := client.Get(server.URL)
+	if !errors.Is(err, errHostNotAllowed) {
+		t.Fatalf("Get(%s) error = %v, want %v", server.URL, err, errHostNotAllowed)
+	}
+}
@@ -1,32 +1,221 @@
 package bot
 
 import (
+	"fmt"
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"kinopoisk-bot/internal/api"
+	"kinopoisk-bot/internal/model"
+	"log/slog"
 	"strconv"
 )
 
-func (b *Bot) createMainMenuKeyboard() tgbotapi.ReplyKeyboardMarkup {
-	return tgbotapi.NewReplyKeyboard(
-		tgbotapi.NewKeyboardButtonRow(
-			tgbotapi.NewKeyboardButton("🎬 Поиск фильмов"),
-			tgbotapi.NewKeyboardButton("👤 Поиск актеров/режиссеров"),
+const menuRandom = "random"
+
+// createMainMenuKeyboard returns the main menu. Its buttons are routed by
+// callback data, so the labels can be translated freely.
+func (b *Bot) createMainMenuKeyboard(lang string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr(lang, "menu.movie_search"), "menu:"+searchTypeMovie),
+			tgbotapi.NewInlineKeyboardButtonData(tr(lang, "menu.person_search"), "menu:"+searchTypePerson),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr(lang, "menu.random"), "menu:"+menuRandom),
 		),
 	)
 }
 
-func (b *Bot) createCancelKeyboard() tgbotapi.InlineKeyboardMarkup {
+func (b *Bot) createCancelKeyboard(lang string) tgbotapi.InlineKeyboardMarkup {
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(
-			tgbotapi.NewInlineKeyboardButtonData("❌ Отменить поиск", "cancel_search"),
+			tgbotapi.NewInlineKeyboardButtonData(tr(lang, "search.cancel"), "cancel_search"),
 		),
 	)
 }
 
-func (b *Bot) createPersonPaginationRow(page int) []tgbotapi.InlineKeyboardButton {
+func (b *Bot) createPersonPaginationRow(sessionID string, page int) []tgbotapi.InlineKeyboardButton {
 	var buttons []tgbotapi.InlineKeyboardButton
 	if page > 1 {
-		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("⬅", "person_page:"+strconv.Itoa(page-1)))
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("⬅", pageCallbackData("person_page", sessionID, page-1)))
 	}
-	buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("➡", "person_page:"+strconv.Itoa(page+1)))
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("➡", pageCallbackData("person_page", sessionID, page+1)))
 	return buttons
 }
+
+func (b *Bot) createMovieCardKeyboard(lang string, movie model.Movie) tgbotapi.InlineKeyboardMarkup {
+	id := strconv.Itoa(movie.Id)
+
+	var media []tgbotapi.InlineKeyboardButton
+	if movie.IsSeries {
+		media = append(media, tgbotapi.NewInlineKeyboardButtonData(tr(lang, "card.seasons"), "seasons:"+id))
+	}
+	if len(movie.Trailers) > 0 {
+		media = append(media, tgbotapi.NewInlineKeyboardButtonData(tr(lang, "card.trailer"), "trailer:"+id))
+	}
+	if len(movie.WatchOptions) > 0 {
+		media = append(media, tgbotapi.NewInlineKeyboardButtonData(tr(lang, "card.watch"), "watch:"+id))
+	}
+	reviews := tgbotapi.NewInlineKeyboardButtonData(tr(lang, "card.reviews"), "reviews:"+id+":1")
+
+	var related []tgbotapi.InlineKeyboardButton
+	if len(movie.Similar) > 0 {
+		related = append(related, tgbotapi.NewInlineKeyboardButtonData(
+			tr(lang, "card.similar"), "related:"+searchTypeSimilar+":"+id))
+	}
+	if len(movie.Sequels) > 0 {
+		related = append(related, tgbotapi.NewInlineKeyboardButtonData(
+			tr(lang, "card.sequels"), "related:"+searchTypeSequels+":"+id))
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	if len(media) > 0 {
+		rows = append(rows, media)
+	}
+	if len(related) > 0 {
+		rows = append(rows, related)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		reviews,
+		tgbotapi.NewInlineKeyboardButtonURL(tr(lang, "card.kinopoisk"), fmt.Sprintf("https://www.kinopoisk.ru/film/%d/", movie.Id)),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// createMoviesKeyboard returns the keyboard under a page of movies: buttons
+// opening each movie, nomination buttons, extraRows and the page buttons.
+func (b *Bot) createMoviesKeyboard(chatID int64, movies []model.Movie, sessionID string, page int, prefix string,
+	extraRows ...[]tgbotapi.InlineKeyboardButton) tgbotapi.InlineKeyboardMarkup {
+	buttons := []tgbotapi.InlineKeyboardButton{}
+	if page > 1 {
+		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("⬅", pageCallbackData(prefix, sessionID, page-1)))
+	}
+	buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("➡", pageCallbackData(prefix, sessionID, page+1)))
+
+	rows := b.createMovieSelectRows(movies)
+	rows = append(rows, b.createNominateRows(chatID, movies)...)
+	rows = append(rows, extraRows...)
+	rows = append(rows, buttons)
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// createMovieSelectRows returns numbered buttons that open the card of each
+// listed movie.
+func (b *Bot) createMovieSelectRows(movies []model.Movie) [][]tgbotapi.InlineKeyboardButton {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, movie := range movies {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("ℹ %d", i+1),
+			"movie:"+strconv.Itoa(movie.Id),
+		))
+		if len(row) == 5 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+// createNominateRows returns buttons for nominating the listed movies when
+// the chat has a movie night collecting nominations.
+func (b *Bot) createNominateRows(chatID int64, movies []model.Movie) [][]tgbotapi.InlineKeyboardButton {
+	night, err := b.redis.GetMovieNight(chatID)
+	if err != nil {
+		slog.Error("Error getting movie night from Redis", "error", err)
+		return nil
+	}
+	if night == nil || night.Status != model.MovieNightNominating {
+		return nil
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, movie := range movies {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("🗳 %d", i+1),
+			"nominate:"+strconv.Itoa(movie.Id),
+		))
+		if len(row) == 5 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	return rows
+}
+
+const personRoleAll = "all"
+
+// personRoles are the professions a filmography can be filtered by. Their
+// labels are the "role.<profession>" messages.
+var personRoles = []string{"actor", "director", "writer", "producer"}
+
+// createPersonCardKeyboard returns buttons that open the person's filmography
+// filtered by each role they have credits in.
+func (b *Bot) createPersonCardKeyboard(lang string, person model.Person) tgbotapi.InlineKeyboardMarkup {
+	prefix := "person_movies:" + strconv.Itoa(person.Id) + ":"
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, role := range personRoles {
+		count := person.RoleCounts[role]
+		if count == 0 {
+			continue
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("%s (%d)", tr(lang, "role."+role), count), prefix+role))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr(lang, "person.all_movies"), prefix+personRoleAll),
+		tgbotapi.NewInlineKeyboardButtonURL(tr(lang, "card.kinopoisk"), fmt.Sprintf("https://www.kinopoisk.ru/name/%d/", person.Id)),
+	))
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+// personMoviesSorts are the filmography sort orders. Their labels are the
+// "sort.<order>" messages.
+var personMoviesSorts = []string{api.SortByPopularity, api.SortByYear, api.SortByRating}
+
+// createPersonMoviesOptionRows returns buttons that switch the role and sort
+// order of a person's filmography. The current choice is marked.
+func (b *Bot) createPersonMoviesOptionRows(lang string, state *model.SearchState) [][]tgbotapi.InlineKeyboardButton {
+	prefix := "person_movies_set:" + state.SessionID + ":"
+
+	allLabel := tr(lang, "role.all")
+	if state.Role == "" {
+		allLabel = "✅ " + allLabel
+	}
+	roleRow := []tgbotapi.InlineKeyboardButton{
+		tgbotapi.NewInlineKeyboardButtonData(allLabel, prefix+"role:"+personRoleAll),
+	}
+	for _, role := range personRoles {
+		label := tr(lang, "role."+role)
+		if role == state.Role {
+			label = "✅ " + label
+		}
+		roleRow = append(roleRow, tgbotapi.NewInlineKeyboardButtonData(label, prefix+"role:"+role))
+	}
+
+	var sortRow []tgbotapi.InlineKeyboardButton
+	for _, sort := range personMoviesSorts {
+		label := tr(lang, "sort."+sort)
+		if sort == state.Sort || (state.Sort == "" && sort == api.SortByPopularity) {
+			label = "✅ " + label
+		}
+		sortRow = append(sortRow, tgbotapi.NewInlineKeyboardButtonData(label, prefix+"sort:"+sort))
+	}
+
+	return [][]tgbotapi.InlineKeyboardButton{roleRow[:3], roleRow[3:], sortRow}
+}
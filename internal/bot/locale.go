@@ -0,0 +1,62 @@
+package bot
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"kinopoisk-bot/internal/i18n"
+	"log/slog"
+)
+
+const languageAuto = "auto"
+
+// tr returns a translated message for the locale.
+func tr(lang string, key string, args ...any) string {
+	return i18n.T(lang, key, args...)
+}
+
+// userLang returns the UI locale for a user: the one chosen in the settings,
+// or the one derived from their Telegram language otherwise.
+func (b *Bot) userLang(user *tgbotapi.User) string {
+	return b.userSettings(user).Language
+}
+
+func (b *Bot) handleLanguageCommand(chatID int64, lang string) {
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, locale := range i18n.Locales {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(locale.Name, "language:"+locale.Code),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr(lang, "language.auto"), "language:"+languageAuto),
+	))
+
+	msg := tgbotapi.NewMessage(chatID, tr(lang, "language.choose"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if _, err := b.sender.Send(msg); err != nil {
+		slog.Error("Error sending language menu", "error", err)
+	}
+}
+
+func (b *Bot) handleLanguageSelect(query *tgbotapi.CallbackQuery, locale string) {
+	settings := b.loadSettings(query.From.ID)
+	switch {
+	case locale == languageAuto:
+		settings.Language = ""
+	case i18n.IsSupported(locale):
+		settings.Language = locale
+	default:
+		slog.Warn("Unsupported locale", "locale", locale)
+		return
+	}
+	if err := b.redis.SaveSettings(query.From.ID, settings); err != nil {
+		slog.Error("Error saving language to Redis", "error", err)
+		return
+	}
+
+	lang := b.userLang(query.From)
+	msg := tgbotapi.NewMessage(query.Message.Chat.ID, tr(lang, "language.changed"))
+	msg.ReplyMarkup = b.createMainMenuKeyboard(lang)
+	if _, err := b.sender.Send(msg); err != nil {
+		slog.Error("Error sending language changed message", "error", err)
+	}
+}
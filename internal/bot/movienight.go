@@ -0,0 +1,457 @@
+package bot
+
+import (
+	"errors"
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"kinopoisk-bot/internal/i18n"
+	"kinopoisk-bot/internal/model"
+	"kinopoisk-bot/internal/redis"
+	"log/slog"
+	"net/http"
+	"strings"
+	"time"
+)
+
+const (
+	defaultVoteDuration     = 2 * time.Hour
+	maxVoteDuration         = 7 * 24 * time.Hour
+	movieNightCheckInterval = 30 * time.Second
+	maxNominees             = 10
+	pollOptionLimit         = 100
+)
+
+var (
+	errNominationsClosed = errors.New("nominations are closed")
+	errAlreadyNominated  = errors.New("movie is already nominated")
+	errTooManyNominees   = errors.New("too many nominees")
+)
+
+func (b *Bot) handleMovieNightCommand(msg *tgbotapi.Message) {
+	chatID := msg.Chat.ID
+	lang := b.userLang(msg.From)
+	if !isGroupChat(msg.Chat) {
+		b.sendText(chatID, tr(lang, "movienight.group_only"))
+		return
+	}
+
+	night, err := b.redis.GetMovieNight(chatID)
+	if err != nil {
+		slog.Error("Error getting movie night from Redis", "error", err)
+		return
+	}
+
+	args := strings.Fields(msg.CommandArguments())
+	subcommand := ""
+	if len(args) > 0 {
+		subcommand = strings.ToLower(args[0])
+	}
+
+	switch subcommand {
+	case "":
+		if night == nil {
+			b.startMovieNight(msg, lang)
+			return
+		}
+		b.sendMovieNightStatus(chatID, lang, night)
+	case "vote":
+		b.startMovieNightVoting(msg, lang, night, args[1:])
+	case "finish":
+		b.handleMovieNightFinish(msg, lang, night)
+	case "cancel":
+		b.cancelMovieNight(msg, lang, night)
+	default:
+		b.sendText(chatID, tr(lang, "movienight.usage"))
+	}
+}
+
+func (b *Bot) startMovieNight(msg *tgbotapi.Message, lang string) {
+	night := model.MovieNight{
+		ChatID:      msg.Chat.ID,
+		OrganizerID: msg.From.ID,
+		Status:      model.MovieNightNominating,
+		Lang:        lang,
+	}
+	if err := b.redis.SaveMovieNight(night); err != nil {
+		slog.Error("Error saving movie night to Redis", "error", err)
+		return
+	}
+
+	b.sendText(msg.Chat.ID, tr(lang, "movienight.started")+"\n\n"+tr(lang, "movienight.usage"))
+}
+
+func (b *Bot) sendMovieNightStatus(chatID int64, lang string, night *model.MovieNight) {
+	var text string
+	switch night.Status {
+	case model.MovieNightVoting:
+		text = tr(lang, "movienight.voting", night.Deadline.UTC().Format("15:04 02.01"))
+	default:
+		text = tr(lang, "movienight.nominating")
+	}
+
+	if len(night.Nominees) == 0 {
+		text += "\n\n" + tr(lang, "movienight.no_nominees")
+	} else {
+		text += "\n\n" + tr(lang, "movienight.nominees") + "\n"
+		for i, nominee := range night.Nominees {
+			text += fmt.Sprintf("%d. %s\n", i+1, nominee.Title)
+		}
+	}
+	b.sendText(chatID, text)
+}
+
+func (b *Bot) startMovieNightVoting(msg *tgbotapi.Message, lang string, night *model.MovieNight, args []string) {
+	chatID := msg.Chat.ID
+	if night == nil {
+		b.sendText(chatID, tr(lang, "movienight.not_started_hint"))
+		return
+	}
+	if night.OrganizerID != msg.From.ID {
+		b.sendText(chatID, tr(lang, "movienight.vote_organizer_only"))
+		return
+	}
+	if night.Status != model.MovieNightNominating {
+		b.sendMovieNightStatus(chatID, lang, night)
+		return
+	}
+	if len(night.Nominees) < 2 {
+		b.sendText(chatID, tr(lang, "movienight.too_few_nominees"))
+		return
+	}
+
+	duration := defaultVoteDuration
+	if len(args) > 0 {
+		parsed, err := time.ParseDuration(args[0])
+		if err != nil || parsed < time.Minute || parsed > maxVoteDuration {
+			b.sendText(chatID, tr(lang, "movienight.bad_duration"))
+			return
+		}
+		duration = parsed
+	}
+
+	// Номинации закрываются до отправки опроса, чтобы список вариантов совпадал с номинантами
+	deadline := time.Now().Add(duration)
+	night, err := b.redis.UpdateMovieNight(chatID, func(night *model.MovieNight) error {
+		if night.Status != model.MovieNightNominating {
+			return errNominationsClosed
+		}
+		night.Status = model.MovieNightVoting
+		night.Deadline = deadline
+		return nil
+	})
+	if err != nil {
+		if errors.Is(err, errNominationsClosed) || errors.Is(err, redis.ErrNoMovieNight) {
+			return
+		}
+		slog.Error("Error saving movie night to Redis", "error", err)
+		return
+	}
+
+	options := make([]string, 0, len(night.Nominees))
+	for _, nominee := range night.Nominees {
+		options = append(options, truncateRunes(nominee.Title, pollOptionLimit))
+	}
+	poll := tgbotapi.NewPoll(chatID, tr(lang, "movienight.poll_question"), options...)
+	poll.IsAnonymous = false
+	sent, err := b.sender.Send(poll)
+	if err != nil {
+		slog.Error("Error sending movie night poll", "error", err)
+		b.sendText(chatID, tr(lang, "movienight.poll_failed"))
+		_, err := b.redis.UpdateMovieNight(chatID, func(night *model.MovieNight) error {
+			night.Status = model.MovieNightNominating
+			night.Deadline = time.Time{}
+			return nil
+		})
+		if err != nil {
+			slog.Error("Error reopening movie night nominations", "error", err)
+		}
+		return
+	}
+
+	_, err = b.redis.UpdateMovieNight(chatID, func(night *model.MovieNight) error {
+		night.PollMessageID = sent.MessageID
+		return nil
+	})
+	if err != nil {
+		slog.Error("Error saving movie night to Redis", "error", err)
+		return
+	}
+	if err := b.redis.ScheduleMovieNight(chatID, deadline); err != nil {
+		slog.Error("Error scheduling movie night", "error", err)
+	}
+}
+
+// handleMovieNightFinish lets the organizer close the vote before the
+// deadline, or retry counting the votes after a failed attempt.
+func (b *Bot) handleMovieNightFinish(msg *tgbotapi.Message, lang string, night *model.MovieNight) {
+	chatID := msg.Chat.ID
+	if night == nil {
+		b.sendText(chatID, tr(lang, "movienight.not_started_hint"))
+		return
+	}
+	if night.OrganizerID != msg.From.ID {
+		b.sendText(chatID, tr(lang, "movienight.finish_organizer_only"))
+		return
+	}
+	if night.Status != model.MovieNightVoting {
+		b.sendText(chatID, tr(lang, "movienight.not_voting"))
+		return
+	}
+	b.finishMovieNight(chatID)
+}
+
+func (b *Bot) cancelMovieNight(msg *tgbotapi.Message, lang string, night *model.MovieNight) {
+	if night == nil {
+		b.sendText(msg.Chat.ID, tr(lang, "movienight.not_started"))
+		return
+	}
+	if night.OrganizerID != msg.From.ID {
+		b.sendText(msg.Chat.ID, tr(lang, "movienight.cancel_organizer_only"))
+		return
+	}
+	if night.Status == model.MovieNightVoting {
+		if _, err := b.sender.StopPoll(tgbotapi.NewStopPoll(night.ChatID, night.PollMessageID)); err != nil {
+			slog.Error("Error stopping movie night poll", "error", err)
+		}
+	}
+	if err := b.redis.DeleteMovieNight(msg.Chat.ID); err != nil {
+		slog.Error("Error deleting movie night from Redis", "error", err)
+	}
+	b.sendText(msg.Chat.ID, tr(lang, "movienight.cancelled"))
+}
+
+func (b *Bot) handleNominate(query *tgbotapi.CallbackQuery, lang string, movieID int) {
+	chatID := query.Message.Chat.ID
+	night, err := b.redis.GetMovieNight(chatID)
+	if err != nil {
+		slog.Error("Error getting movie night from Redis", "error", err)
+		b.answerCallback(query, "")
+		return
+	}
+	if night == nil || night.Status != model.MovieNightNominating {
+		b.answerCallbackAlert(query, tr(lang, "movienight.nominations_closed"))
+		return
+	}
+	for _, nominee := range night.Nominees {
+		if nominee.MovieID == movieID {
+			b.answerCallback(query, tr(lang, "movienight.already_nominated"))
+			return
+		}
+	}
+	if len(night.Nominees) >= maxNominees {
+		b.answerCallbackAlert(query, tr(lang, "movienight.too_many_nominees", maxNominees))
+		return
+	}
+
+	movie, err := b.kinopoisk.GetMovieByID(movieID)
+	if err != nil {
+		b.answerCallbackAlert(query, tr(lang, "movie.load_failed"))
+		return
+	}
+	// Номинанты видны всей группе, поэтому названия и фильтр для взрослых
+	// берутся из настроек организатора.
+	organizer := b.loadSettings(night.OrganizerID)
+	if organizer.HideAdult && movie.AgeRating >= adultAgeRating {
+		b.answerCallbackAlert(query, tr(lang, "movienight.adult_hidden"))
+		return
+	}
+	movie.Title = displayTitle(organizer.TitleMode, *movie)
+
+	title := fmt.Sprintf("%s (%s)", movie.Title, movie.Year)
+	// Пока грузился фильм, ночь могли закрыть или номинировать тот же фильм, поэтому проверки повторяются
+	night, err = b.redis.UpdateMovieNight(chatID, func(night *model.MovieNight) error {
+		if night.Status != model.MovieNightNominating {
+			return errNominationsClosed
+		}
+		for _, nominee := range night.Nominees {
+			if nominee.MovieID == movieID {
+				return errAlreadyNominated
+			}
+		}
+		if len(night.Nominees) >= maxNominees {
+			return errTooManyNominees
+		}
+		night.Nominees = append(night.Nominees, model.Nominee{
+			MovieID: movieID,
+			Title:   title,
+			UserID:  query.From.ID,
+		})
+		return nil
+	})
+	switch {
+	case err == nil:
+	case errors.Is(err, errNominationsClosed), errors.Is(err, redis.ErrNoMovieNight):
+		b.answerCallbackAlert(query, tr(lang, "movienight.nominations_closed"))
+		return
+	case errors.Is(err, errAlreadyNominated):
+		b.answerCallback(query, tr(lang, "movienight.already_nominated"))
+		return
+	case errors.Is(err, errTooManyNominees):
+		b.answerCallbackAlert(query, tr(lang, "movienight.too_many_nominees", maxNominees))
+		return
+	default:
+		slog.Error("Error saving movie night to Redis", "error", err)
+		b.answerCallback(query, "")
+		return
+	}
+
+	b.answerCallback(query, tr(lang, "movienight.nominated", title))
+	b.sendText(chatID, tr(night.Lang, "movienight.nominee_added",
+		query.From.FirstName, title, len(night.Nominees), maxNominees))
+}
+
+// watchMovieNights closes movie night polls once their deadline passes.
+// Deadlines live in Redis, so polls started before a restart are closed too.
+func (b *Bot) watchMovieNights() {
+	defer b.wg.Done()
+
+	ticker := time.NewTicker(movieNightCheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-b.stopChan:
+			return
+		case now := <-ticker.C:
+			chatIDs, err := b.redis.DueMovieNights(now)
+			if err != nil {
+				slog.Error("Error getting due movie nights", "error", err)
+				continue
+			}
+			for _, chatID := range chatIDs {
+				// Итоги подводятся в горутине чата, чтобы не пересечься с /movienight finish.
+				// Если очередь чата занята, ночь останется в Redis до следующей проверки
+				b.wg.Add(1)
+				if !b.handlers.TryGo(chatID, func() {
+					defer b.wg.Done()
+					b.finishMovieNight(chatID)
+				}) {
+					b.wg.Done()
+				}
+			}
+		}
+	}
+}
+
+// finishMovieNight stops the poll and announces the winner. The night stays
+// in Redis until the poll is stopped, so after a transient failure the
+// deadline watcher or /movienight finish tries again.
+func (b *Bot) finishMovieNight(chatID int64) {
+	night, err := b.redis.GetMovieNight(chatID)
+	if err != nil {
+		slog.Error("Error getting movie night from Redis", "error", err)
+		return
+	}
+	if night == nil || night.Status != model.MovieNightVoting {
+		return
+	}
+
+	poll, err := b.sender.StopPoll(tgbotapi.NewStopPoll(chatID, night.PollMessageID))
+	if err != nil {
+		var tgErr *tgbotapi.Error
+		if !errors.As(err, &tgErr) || tgErr.Code != http.StatusBadRequest {
+			slog.Warn("Error stopping movie night poll, will retry", "chat", chatID, "error", err)
+			return
+		}
+		// Опрос удален или уже закрыт, повторять бесполезно
+		slog.Error("Error stopping movie night poll", "chat", chatID, "error", err)
+		taken, err := b.redis.TakeMovieNight(chatID, night.PollMessageID)
+		if err != nil {
+			slog.Error("Error taking movie night from Redis", "error", err)
+			return
+		}
+		if taken != nil {
+			b.sendText(chatID, tr(night.Lang, "movienight.poll_unavailable"))
+		}
+		return
+	}
+
+	// Ночь забирается из Redis целиком, так что номинация после этого ее уже не вернет
+	night, err = b.redis.TakeMovieNight(chatID, night.PollMessageID)
+	if err != nil {
+		slog.Error("Error taking movie night from Redis", "error", err)
+		return
+	}
+	if night == nil {
+		return
+	}
+
+	winners := pollWinners(poll, len(night.Nominees))
+	if len(winners) == 0 {
+		b.sendText(chatID, tr(night.Lang, "movienight.no_votes"))
+		return
+	}
+
+	// При ничьей побеждает номинированный раньше, и это говорится в итогах
+	nominee := night.Nominees[winners[0]]
+	votes := poll.Options[winners[0]].VoterCount
+	if len(winners) > 1 {
+		titles := make([]string, len(winners))
+		for i, winner := range winners {
+			titles[i] = "«" + night.Nominees[winner].Title + "»"
+		}
+		b.sendText(chatID, tr(night.Lang, "movienight.tie",
+			strings.Join(titles, ", "), votes, nominee.Title))
+	} else {
+		b.sendText(chatID, tr(night.Lang, "movienight.winner", nominee.Title, votes))
+	}
+	b.notifyMovieNightResult(night, nominee)
+
+	movie, err := b.kinopoisk.GetMovieByID(nominee.MovieID)
+	if err != nil {
+		return
+	}
+	movie.Title = displayTitle(b.loadSettings(night.OrganizerID).TitleMode, *movie)
+	b.sendMovieCard(chatID, night.Lang, *movie)
+}
+
+// notifyMovieNightResult privately tells nominators who opted in to movie
+// night notifications which movie won.
+func (b *Bot) notifyMovieNightResult(night *model.MovieNight, winner model.Nominee) {
+	notified := make(map[int64]bool)
+	for _, nominee := range night.Nominees {
+		if notified[nominee.UserID] {
+			continue
+		}
+		notified[nominee.UserID] = true
+
+		settings := b.loadSettings(nominee.UserID)
+		if !settings.NotifyMovieNights {
+			continue
+		}
+		lang := settings.Language
+		if !i18n.IsSupported(lang) {
+			lang = night.Lang
+		}
+		// Личный чат с пользователем имеет тот же ID, что и сам пользователь.
+		b.sendText(nominee.UserID, tr(lang, "movienight.result_notice", winner.Title))
+	}
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
+
+// pollWinners returns the indexes of the nominees with the most votes, in
+// nomination order, or none when nobody voted.
+func pollWinners(poll tgbotapi.Poll, nominees int) []int {
+	most := 0
+	var winners []int
+	for i, option := range poll.Options {
+		if i >= nominees {
+			break
+		}
+		switch {
+		case option.VoterCount > most:
+			most = option.VoterCount
+			winners = []int{i}
+		case option.VoterCount == most && most > 0:
+			winners = append(winners, i)
+		}
+	}
+	return winners
+}
@@ -0,0 +1,94 @@
+package bot
+
+import (
+	"bytes"
+	"fmt"
+	"golang.org/x/image/draw"
+	_ "golang.org/x/image/webp"
+	"image"
+	_ "image/gif"
+	"image/jpeg"
+	"sync/atomic"
+)
+
+const (
+	defaultPosterMaxSize = 800
+	defaultPosterQuality = 80
+	// posterMaxPixels caps the decoded size of a poster. A small file can
+	// declare huge dimensions and take gigabytes of memory once decoded.
+	posterMaxPixels = 40_000_000
+	// posterMaxBytes caps the size of a downloaded poster file.
+	posterMaxBytes = 5 << 20
+)
+
+// Настройки меняются командой /reload во время работы, поэтому атомарные
+var posterMaxSize, posterQuality atomic.Int64
+
+func init() {
+	posterMaxSize.Store(defaultPosterMaxSize)
+	posterQuality.Store(defaultPosterQuality)
+}
+
+// SetPosterProcessing sets the longest side posters are downscaled to and the
+// JPEG quality they are re-encoded with. Invalid values reset the defaults.
+func SetPosterProcessing(maxSize, quality int) {
+	if maxSize <= 0 {
+		maxSize = defaultPosterMaxSize
+	}
+	if quality <= 0 || quality > 100 {
+		quality = defaultPosterQuality
+	}
+	posterMaxSize.Store(int64(maxSize))
+	posterQuality.Store(int64(quality))
+}
+
+// processPoster decodes a downloaded poster, downscales it to posterMaxSize
+// and re-encodes it as JPEG. WebP and PNG are converted, animated GIFs are
+// reduced to their first frame.
+func processPoster(data []byte) ([]byte, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode poster config: %w", err)
+	}
+	if err := checkPosterDimensions(config); err != nil {
+		return nil, err
+	}
+
+	src, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("decode poster: %w", err)
+	}
+
+	bounds := src.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 {
+		return nil, fmt.Errorf("empty poster")
+	}
+	maxSize := int(posterMaxSize.Load())
+	if longest := max(width, height); longest > maxSize {
+		width = max(width*maxSize/longest, 1)
+		height = max(height*maxSize/longest, 1)
+	}
+
+	// JPEG не поддерживает прозрачность, поэтому рисуем на белом фоне
+	dst := image.NewRGBA(image.Rect(0, 0, width, height))
+	draw.Draw(dst, dst.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.CatmullRom.Scale(dst, dst.Bounds(), src, bounds, draw.Over, nil)
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, dst, &jpeg.Options{Quality: int(posterQuality.Load())}); err != nil {
+		return nil, fmt.Errorf("encode poster: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// checkPosterDimensions rejects images too large to decode safely.
+func checkPosterDimensions(config image.Config) error {
+	if config.Width <= 0 || config.Height <= 0 {
+		return fmt.Errorf("empty poster")
+	}
+	if int64(config.Width)*int64(config.Height) > posterMaxPixels {
+		return fmt.Errorf("poster too large: %dx%d", config.Width, config.Height)
+	}
+	return nil
+}
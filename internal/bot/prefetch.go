@@ -0,0 +1,100 @@
+package bot
+
+import (
+	"context"
+	"kinopoisk-bot/internal/model"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	// prefetchConcurrency caps poster downloads of all prefetches together,
+	// so warming caches never competes with pages users are waiting for.
+	prefetchConcurrency = 3
+	// prefetchIdleTimeout cancels a prefetch when nothing happens in the
+	// chat for this long.
+	prefetchIdleTimeout = 2 * time.Minute
+)
+
+var prefetchSlots = make(chan struct{}, prefetchConcurrency)
+
+// pageLoader loads the page after the one being shown. It should give up once
+// ctx is done.
+type pageLoader func(ctx context.Context) ([]model.Movie, error)
+
+type prefetchTask struct {
+	cancel context.CancelFunc
+	// idle cancels the prefetch, every update from the chat postpones it
+	idle *time.Timer
+}
+
+// prefetchNextPage loads the next page of results in the background, which
+// fills the API response cache, and warms the poster cache for it. Only the
+// latest prefetch of a chat is kept running. Pages shorter than limit are the
+// last ones and aren't followed by a prefetch.
+func (b *Bot) prefetchNextPage(chatID int64, settings model.Settings, shown int, limit int, next pageLoader) {
+	if next == nil || shown < limit {
+		return
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	task := &prefetchTask{cancel: cancel, idle: time.AfterFunc(prefetchIdleTimeout, cancel)}
+	if previous, ok := b.prefetches.Swap(chatID, task); ok {
+		previous.(*prefetchTask).stop()
+	}
+
+	go func() {
+		defer func() {
+			task.stop()
+			b.prefetches.CompareAndDelete(chatID, task)
+		}()
+
+		movies, err := next(ctx)
+		if err != nil || len(movies) == 0 || ctx.Err() != nil {
+			return
+		}
+		// В компактном режиме постеры не нужны
+		if settings.Layout == layoutCompact {
+			return
+		}
+
+		var wg sync.WaitGroup
+		for _, movie := range visibleMovies(settings, movies) {
+			if movie.Poster == "" {
+				continue
+			}
+			select {
+			case prefetchSlots <- struct{}{}:
+			case <-ctx.Done():
+				wg.Wait()
+				return
+			case <-b.stopChan:
+				wg.Wait()
+				return
+			}
+			wg.Add(1)
+			go func(url string) {
+				defer func() {
+					<-prefetchSlots
+					wg.Done()
+				}()
+				GetSafePoster(url)
+			}(movie.Poster)
+		}
+		wg.Wait()
+		slog.Debug("Prefetched next page", "chat_id", chatID, "movies", len(movies))
+	}()
+}
+
+func (t *prefetchTask) stop() {
+	t.idle.Stop()
+	t.cancel()
+}
+
+// touchPrefetch postpones the idle cancellation of the chat's prefetch.
+func (b *Bot) touchPrefetch(chatID int64) {
+	if task, ok := b.prefetches.Load(chatID); ok {
+		task.(*prefetchTask).idle.Reset(prefetchIdleTimeout)
+	}
+}
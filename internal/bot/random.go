@@ -0,0 +1,262 @@
+package bot
+
+import (
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"kinopoisk-bot/internal/model"
+	"log/slog"
+	"strconv"
+	"strings"
+)
+
+// randomAttempts bounds rerolls when the picked title is already in the watchlist.
+const randomAttempts = 5
+
+// randomGenres maps the genre names the API filters by to message keys for
+// their labels.
+var randomGenres = []struct {
+	name string
+	key  string
+}{
+	{"драма", "genre.drama"},
+	{"комедия", "genre.comedy"},
+	{"боевик", "genre.action"},
+	{"триллер", "genre.thriller"},
+	{"ужасы", "genre.horror"},
+	{"фантастика", "genre.scifi"},
+	{"мелодрама", "genre.romance"},
+	{"детектив", "genre.detective"},
+	{"мультфильм", "genre.animation"},
+	{"приключения", "genre.adventure"},
+	{"фэнтези", "genre.fantasy"},
+	{"криминал", "genre.crime"},
+	{"документальный", "genre.documentary"},
+	{"аниме", "genre.anime"},
+}
+
+var randomRatings = []float64{6, 7, 8}
+
+var randomDecades = []struct {
+	key      string
+	from, to int
+}{
+	{"decade.before1980", 1900, 1979},
+	{"decade.1980s", 1980, 1989},
+	{"decade.1990s", 1990, 1999},
+	{"decade.2000s", 2000, 2009},
+	{"decade.2010s", 2010, 2019},
+	{"decade.2020s", 2020, 0},
+}
+
+func (b *Bot) handleRandomCommand(chatID int64, user *tgbotapi.User) {
+	userID := user.ID
+	settings := b.userSettings(user)
+	lang := settings.Language
+	filter, err := b.redis.GetRandomFilter(userID)
+	if err != nil {
+		slog.Error("Error getting random filter from Redis", "error", err)
+	}
+
+	var movie *model.Movie
+	for attempt := 0; attempt < randomAttempts; attempt++ {
+		movie, err = b.kinopoisk.RandomMovie(filter, settings.HideAdult)
+		if err != nil || movie == nil {
+			break
+		}
+		if !filter.ExcludeWatchlist {
+			break
+		}
+		inWatchlist, err := b.redis.IsInWatchlist(userID, movie.Id)
+		if err != nil {
+			slog.Error("Error checking watchlist", "error", err)
+			break
+		}
+		if !inWatchlist {
+			break
+		}
+		movie = nil
+	}
+
+	if movie == nil {
+		msg := tgbotapi.NewMessage(chatID, tr(lang, "random.not_found"))
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(
+			tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonData(tr(lang, "random.filter"), "random_filter"),
+				tgbotapi.NewInlineKeyboardButtonData(tr(lang, "random.retry"), "random"),
+			),
+		)
+		if _, err := b.sender.Send(msg); err != nil {
+			slog.Error("Error sending random not found message", "error", err)
+		}
+		return
+	}
+
+	movie.Title = displayTitle(settings.TitleMode, *movie)
+	b.sendMovieCard(chatID, lang, *movie,
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr(lang, "random.more"), "random"),
+			tgbotapi.NewInlineKeyboardButtonData(tr(lang, "random.watchlist_add"), "watchlist_add:"+strconv.Itoa(movie.Id)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr(lang, "random.filter_current", formatRandomFilter(lang, filter)), "random_filter"),
+		),
+	)
+}
+
+func (b *Bot) handleRandomFilterMenu(chatID int64, lang string, userID int64, messageID int) {
+	filter, err := b.redis.GetRandomFilter(userID)
+	if err != nil {
+		slog.Error("Error getting random filter from Redis", "error", err)
+	}
+
+	text := tr(lang, "random.filter_title", formatRandomFilter(lang, filter))
+	keyboard := createRandomFilterKeyboard(lang, filter)
+	if messageID != 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, keyboard)
+		if _, err := b.sender.Send(edit); err != nil {
+			slog.Error("Error editing random filter menu", "error", err)
+		}
+		return
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = keyboard
+	if _, err := b.sender.Send(msg); err != nil {
+		slog.Error("Error sending random filter menu", "error", err)
+	}
+}
+
+// handleRandomFilterSet applies a "random_set:<field>:<value>" callback and
+// redraws the filter menu in place.
+func (b *Bot) handleRandomFilterSet(query *tgbotapi.CallbackQuery, lang string, field string, value string) {
+	userID := query.From.ID
+	filter, err := b.redis.GetRandomFilter(userID)
+	if err != nil {
+		slog.Error("Error getting random filter from Redis", "error", err)
+		return
+	}
+
+	index, _ := strconv.Atoi(value)
+	switch field {
+	case "genre":
+		filter.Genre = ""
+		if index >= 0 && index < len(randomGenres) {
+			filter.Genre = randomGenres[index].name
+		}
+	case "rating":
+		filter.MinRating = 0
+		if index >= 0 && index < len(randomRatings) {
+			filter.MinRating = randomRatings[index]
+		}
+	case "years":
+		filter.YearFrom, filter.YearTo = 0, 0
+		if index >= 0 && index < len(randomDecades) {
+			filter.YearFrom, filter.YearTo = randomDecades[index].from, randomDecades[index].to
+		}
+	case "watchlist":
+		filter.ExcludeWatchlist = !filter.ExcludeWatchlist
+	}
+
+	if err := b.redis.SaveRandomFilter(userID, filter); err != nil {
+		slog.Error("Error saving random filter to Redis", "error", err)
+		return
+	}
+	b.handleRandomFilterMenu(query.Message.Chat.ID, lang, userID, query.Message.MessageID)
+}
+
+func (b *Bot) handleWatchlistAdd(query *tgbotapi.CallbackQuery, lang string, movieID int) {
+	if err := b.redis.AddToWatchlist(query.From.ID, movieID); err != nil {
+		slog.Error("Error adding movie to watchlist", "error", err)
+		b.answerCallback(query, "")
+		return
+	}
+	b.answerCallback(query, tr(lang, "random.watchlist_added"))
+}
+
+func createRandomFilterKeyboard(lang string, filter model.RandomFilter) tgbotapi.InlineKeyboardMarkup {
+	var rows [][]tgbotapi.InlineKeyboardButton
+
+	var row []tgbotapi.InlineKeyboardButton
+	for i, genre := range randomGenres {
+		label := tr(lang, genre.key)
+		if genre.name == filter.Genre {
+			label = "✅ " + label
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, "random_set:genre:"+strconv.Itoa(i)))
+		if len(row) == 3 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	row = append(row, tgbotapi.NewInlineKeyboardButtonData(tr(lang, "random.any_genre"), "random_set:genre:-1"))
+	rows = append(rows, row)
+
+	row = nil
+	for i, rating := range randomRatings {
+		label := tr(lang, "random.rating_from", rating)
+		if rating == filter.MinRating {
+			label = "✅ " + label
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, "random_set:rating:"+strconv.Itoa(i)))
+	}
+	row = append(row, tgbotapi.NewInlineKeyboardButtonData(tr(lang, "random.any_rating"), "random_set:rating:-1"))
+	rows = append(rows, row)
+
+	row = nil
+	for i, decade := range randomDecades {
+		label := tr(lang, decade.key)
+		if decade.from == filter.YearFrom && decade.to == filter.YearTo {
+			label = "✅ " + label
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label, "random_set:years:"+strconv.Itoa(i)))
+		if len(row) == 3 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr(lang, "random.any_years"), "random_set:years:-1"),
+	))
+
+	watchlistLabel := tr(lang, "random.keep_watchlist")
+	if filter.ExcludeWatchlist {
+		watchlistLabel = tr(lang, "random.exclude_watchlist")
+	}
+	rows = append(rows,
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(watchlistLabel, "random_set:watchlist:0")),
+		tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(tr(lang, "random.pick"), "random")),
+	)
+	return tgbotapi.NewInlineKeyboardMarkup(rows...)
+}
+
+func formatRandomFilter(lang string, filter model.RandomFilter) string {
+	var parts []string
+	if filter.Genre != "" {
+		parts = append(parts, genreLabel(lang, filter.Genre))
+	}
+	if filter.MinRating > 0 {
+		parts = append(parts, tr(lang, "random.summary_rating", filter.MinRating))
+	}
+	switch {
+	case filter.YearFrom > 0 && filter.YearTo > 0:
+		parts = append(parts, fmt.Sprintf("%d–%d", filter.YearFrom, filter.YearTo))
+	case filter.YearFrom > 0:
+		parts = append(parts, tr(lang, "random.summary_since", filter.YearFrom))
+	}
+	if filter.ExcludeWatchlist {
+		parts = append(parts, tr(lang, "random.summary_no_watchlist"))
+	}
+	if len(parts) == 0 {
+		return tr(lang, "random.summary_any")
+	}
+	return strings.Join(parts, ", ")
+}
+
+func genreLabel(lang string, name string) string {
+	for _, genre := range randomGenres {
+		if genre.name == name {
+			return tr(lang, genre.key)
+		}
+	}
+	return name
+}
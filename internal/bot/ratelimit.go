@@ -0,0 +1,60 @@
+package bot
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"log/slog"
+	"time"
+)
+
+// Per-user limits. Searches are the expensive part: an API request and up
+// to a page of poster uploads each, and so is every pagination press.
+const (
+	searchRateLimit    = 10
+	searchRateWindow   = time.Minute
+	callbackRateLimit  = 30
+	callbackRateWindow = time.Minute
+	// callbackDebounce drops repeated presses of the same button
+	callbackDebounce = time.Second
+)
+
+// allowSearch reports whether the user may start another search. Admins
+// aren't limited, and Redis errors let the search through.
+func (b *Bot) allowSearch(user *tgbotapi.User) bool {
+	if b.isAdmin(user) {
+		return true
+	}
+	allowed, err := b.redis.AllowRate("search", user.ID, searchRateLimit, searchRateWindow)
+	if err != nil {
+		slog.Error("Error checking search rate limit in Redis", "error", err)
+	}
+	return allowed
+}
+
+// allowCallback reports whether the button press should be handled. Presses
+// over the limit get an alert, repeated presses of the same button a short
+// notification.
+func (b *Bot) allowCallback(query *tgbotapi.CallbackQuery, lang string) bool {
+	if b.isAdmin(query.From) {
+		return true
+	}
+
+	first, err := b.redis.Debounce(query.From.ID, query.Data, callbackDebounce)
+	if err != nil {
+		slog.Error("Error debouncing callback in Redis", "error", err)
+		first = true
+	}
+	if !first {
+		b.answerCallback(query, tr(lang, "rate_limit.repeated"))
+		return false
+	}
+
+	allowed, err := b.redis.AllowRate("callback", query.From.ID, callbackRateLimit, callbackRateWindow)
+	if err != nil {
+		slog.Error("Error checking callback rate limit in Redis", "error", err)
+	}
+	if !allowed {
+		b.answerCallbackAlert(query, tr(lang, "rate_limit.too_often"))
+		return false
+	}
+	return true
+}
@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"context"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"kinopoisk-bot/internal/model"
+	"log/slog"
+)
+
+func (b *Bot) handleMovieSelect(chatID int64, settings model.Settings, movieID int) {
+	movie, err := b.kinopoisk.GetMovieByID(movieID)
+	if err != nil {
+		b.sendText(chatID, tr(settings.Language, "movie.load_failed"))
+		return
+	}
+	if settings.HideAdult && movie.AgeRating >= adultAgeRating {
+		b.sendText(chatID, tr(settings.Language, "movie.hidden"))
+		return
+	}
+	movie.Title = displayTitle(settings.TitleMode, *movie)
+	b.sendMovieCard(chatID, settings.Language, *movie)
+}
+
+func (b *Bot) handleRelatedSelect(chatID int64, settings model.Settings, userID int64, relation string, movieID int) {
+	if relation != searchTypeSimilar && relation != searchTypeSequels {
+		slog.Warn("Unknown movie relation", "relation", relation)
+		return
+	}
+
+	session, err := b.startSession(chatID, model.SearchState{
+		Type:    relation,
+		MovieID: movieID,
+		UserID:  userID,
+		Limit:   settings.ResultsPerPage,
+	})
+	if err != nil {
+		slog.Error("Error saving related session to Redis", "error", err)
+		return
+	}
+	b.sendRelatedPage(chatID, settings, session, 1)
+}
+
+func (b *Bot) handleRelatedPagination(chatID int64, settings model.Settings, state *model.SearchState, page int) {
+	if state.Type != searchTypeSimilar && state.Type != searchTypeSequels {
+		slog.Warn("Invalid state for related movies", "state", state)
+		b.sendStateExpired(chatID, settings.Language)
+		return
+	}
+	b.sendRelatedPage(chatID, settings, state, page)
+}
+
+// sendRelatedPage pages through similar movies or sequels of a movie. The API
+// returns them all at once with the movie, so pagination happens locally.
+func (b *Bot) sendRelatedPage(chatID int64, settings model.Settings, state *model.SearchState, page int) {
+	movie, err := b.kinopoisk.GetMovieByID(state.MovieID)
+	if err != nil {
+		b.sendText(chatID, tr(settings.Language, "movie.load_failed"))
+		return
+	}
+
+	related := movie.Similar
+	if state.Type == searchTypeSequels {
+		related = movie.Sequels
+	}
+	if settings.HideAdult {
+		related = b.visibleRelated(settings, related)
+	}
+
+	limit := pageSize(state)
+	start := (page - 1) * limit
+	if page < 1 || start >= len(related) {
+		msg := tgbotapi.NewMessage(chatID, tr(settings.Language, "movies.no_more"))
+		if _, err := b.sender.Send(msg); err != nil {
+			slog.Error("Error sending no more movies message", "error", err)
+		}
+		return
+	}
+	end := min(start+limit, len(related))
+
+	state.Page = page
+	if err := b.redis.SaveSession(chatID, *state); err != nil {
+		slog.Error("Error saving session to Redis", "error", err)
+	}
+
+	applyTitleMode(settings.TitleMode, related)
+	// Все связанные фильмы уже загружены, подгрузка только прогревает постеры следующей страницы
+	b.sendMovies(chatID, settings, related[start:end], state.SessionID, page, limit, "related_page",
+		func(ctx context.Context) ([]model.Movie, error) {
+			return related[end:min(end+limit, len(related))], nil
+		})
+}
+
+// visibleRelated applies the adult content filter to similar movies or
+// sequels. They come without age ratings, so these are looked up first, and
+// the whole list is filtered before paging to keep pages full.
+func (b *Bot) visibleRelated(settings model.Settings, related []model.Movie) []model.Movie {
+	ids := make([]int, len(related))
+	for i, movie := range related {
+		ids[i] = movie.Id
+	}
+	ratings, err := b.kinopoisk.AgeRatings(ids)
+	if err != nil {
+		// Без возрастных рейтингов нельзя проверить фильмы, поэтому они не показываются
+		return nil
+	}
+	for i := range related {
+		related[i].AgeRating = ratings[related[i].Id]
+	}
+	return visibleMovies(settings, related)
+}
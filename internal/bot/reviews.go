@@ -0,0 +1,141 @@
+package bot
+
+import (
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"html"
+	"kinopoisk-bot/internal/model"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+)
+
+const (
+	reviewsPerPage     = 5
+	reviewPreviewLimit = 300
+	telegramTextLimit  = 4096
+)
+
+var reviewTypes = map[string]string{
+	"Позитивный":  "👍",
+	"Негативный":  "👎",
+	"Нейтральный": "😐",
+}
+
+func (b *Bot) handleReviews(chatID int64, lang string, messageID int, movieID int, page int) {
+	page = max(page, 1)
+	reviews, pages, err := b.kinopoisk.GetReviews(movieID, page, reviewsPerPage)
+	if err != nil {
+		b.sendText(chatID, tr(lang, "reviews.load_failed"))
+		return
+	}
+	if len(reviews) == 0 {
+		b.sendText(chatID, tr(lang, "reviews.empty"))
+		return
+	}
+
+	text := tr(lang, "reviews.title", page, max(pages, 1)) + "\n"
+	var readRow []tgbotapi.InlineKeyboardButton
+	for i, review := range reviews {
+		text += "\n" + formatReviewPreview(i+1, review) + "\n"
+		readRow = append(readRow, tgbotapi.NewInlineKeyboardButtonData(
+			fmt.Sprintf("📖 %d", i+1), "review:"+strconv.Itoa(review.Id)))
+	}
+
+	rows := [][]tgbotapi.InlineKeyboardButton{readRow}
+	var nav []tgbotapi.InlineKeyboardButton
+	if page > 1 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("⬅",
+			fmt.Sprintf("reviews:%d:%d", movieID, page-1)))
+	}
+	if page < pages {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("➡",
+			fmt.Sprintf("reviews:%d:%d", movieID, page+1)))
+	}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+
+	b.sendOrEdit(chatID, messageID, text, tgbotapi.NewInlineKeyboardMarkup(rows...))
+}
+
+// handleReviewRead sends the full text of a review, split into several
+// messages when it doesn't fit into one.
+func (b *Bot) handleReviewRead(chatID int64, lang string, reviewID int) {
+	review, err := b.kinopoisk.GetReviewByID(reviewID)
+	if err != nil {
+		b.sendText(chatID, tr(lang, "review.load_failed"))
+		return
+	}
+
+	text := formatReviewHeader(*review) + "\n\n" + cleanReviewText(review.Text)
+	for _, chunk := range splitText(text, telegramTextLimit) {
+		b.sendText(chatID, chunk)
+	}
+}
+
+func formatReviewHeader(review model.Review) string {
+	header := reviewTypes[review.Type]
+	if header == "" {
+		header = "💬"
+	}
+	if review.Title != "" {
+		header += " " + review.Title
+	}
+	if review.Author != "" {
+		header += "\n✍ " + review.Author
+	}
+	if review.Date != "" {
+		header += ", " + review.Date
+	}
+	return header
+}
+
+func formatReviewPreview(index int, review model.Review) string {
+	return fmt.Sprintf("<b>%d. %s</b>\n%s", index,
+		html.EscapeString(formatReviewHeader(review)),
+		html.EscapeString(truncateRunes(cleanReviewText(review.Text), reviewPreviewLimit)))
+}
+
+// cleanReviewText strips the HTML markup reviews come with.
+func cleanReviewText(text string) string {
+	text = strings.ReplaceAll(text, "<br>", "\n")
+	return strings.TrimSpace(html.UnescapeString(htmlTagPattern.ReplaceAllString(text, "")))
+}
+
+// splitText splits text into chunks of at most limit UTF-16 code units, which
+// is how Telegram measures messages, preferring to break at paragraph
+// boundaries.
+func splitText(text string, limit int) []string {
+	var chunks []string
+	runes := []rune(text)
+	for utf16Len(runes) > limit {
+		// Эмодзи и другие символы вне BMP занимают две единицы UTF-16
+		end, units := 0, 0
+		for units+utf16.RuneLen(runes[end]) <= limit {
+			units += utf16.RuneLen(runes[end])
+			end++
+		}
+		cut := end
+		for i := end; i > end/2; i-- {
+			if runes[i] == '\n' {
+				cut = i
+				break
+			}
+		}
+		chunks = append(chunks, strings.TrimSpace(string(runes[:cut])))
+		runes = runes[cut:]
+	}
+	if rest := strings.TrimSpace(string(runes)); rest != "" {
+		chunks = append(chunks, rest)
+	}
+	return chunks
+}
+
+func utf16Len(runes []rune) int {
+	length := 0
+	for _, r := range runes {
+		length += utf16.RuneLen(r)
+	}
+	return length
+}
@@ -0,0 +1,35 @@
+package bot
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestSplitTextCountsUTF16Units(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+	}{
+		{"cyrillic", strings.Repeat("отзыв ", 2000)},
+		{"emoji", strings.Repeat("🎬", 5000)},
+		{"paragraphs", strings.Repeat("Абзац с эмодзи 🍿🎥\n", 700)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks := splitText(tt.text, telegramTextLimit)
+			if len(chunks) < 2 {
+				t.Fatalf("got %d chunks, want the text split", len(chunks))
+			}
+			for i, chunk := range chunks {
+				if units := utf16Len([]rune(chunk)); units > telegramTextLimit {
+					t.Errorf("chunk %d is %d UTF-16 units, limit %d", i, units, telegramTextLimit)
+				}
+			}
+			// Пробелы на границах частей обрезаются, поэтому сравниваем без них
+			got := strings.Join(strings.Fields(strings.Join(chunks, " ")), "")
+			if want := strings.Join(strings.Fields(tt.text), ""); got != want {
+				t.Error("chunks lost some of the text")
+			}
+		})
+	}
+}
@@ -0,0 +1,110 @@
+package bot
+
+import (
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"kinopoisk-bot/internal/model"
+	"log/slog"
+	"strconv"
+)
+
+const episodesPerPage = 5
+
+// handleSeasons shows the list of a series' seasons. When messageID is set the
+// existing message is edited, so the browser stays in a single message.
+func (b *Bot) handleSeasons(chatID int64, lang string, messageID int, movieID int) {
+	seasons, err := b.kinopoisk.GetSeasons(movieID)
+	if err != nil {
+		b.sendText(chatID, tr(lang, "seasons.load_failed"))
+		return
+	}
+	if len(seasons) == 0 {
+		b.sendText(chatID, tr(lang, "seasons.empty"))
+		return
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for _, season := range seasons {
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(
+			tr(lang, "seasons.button", season.Number, season.EpisodesCount),
+			fmt.Sprintf("season:%d:%d:1", movieID, season.Number),
+		))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	b.sendOrEdit(chatID, messageID, tr(lang, "seasons.choose"), tgbotapi.NewInlineKeyboardMarkup(rows...))
+}
+
+func (b *Bot) handleSeasonEpisodes(chatID int64, lang string, messageID int, movieID int, seasonNumber int, page int) {
+	seasons, err := b.kinopoisk.GetSeasons(movieID)
+	if err != nil {
+		b.sendText(chatID, tr(lang, "seasons.load_failed"))
+		return
+	}
+
+	var season *model.Season
+	for i := range seasons {
+		if seasons[i].Number == seasonNumber {
+			season = &seasons[i]
+			break
+		}
+	}
+	if season == nil {
+		b.sendText(chatID, tr(lang, "seasons.not_found"))
+		return
+	}
+
+	pages := max(1, (len(season.Episodes)+episodesPerPage-1)/episodesPerPage)
+	page = min(max(page, 1), pages)
+	start := (page - 1) * episodesPerPage
+	end := min(start+episodesPerPage, len(season.Episodes))
+
+	text := formatSeason(lang, *season, season.Episodes[start:end], page, pages)
+
+	var nav []tgbotapi.InlineKeyboardButton
+	if page > 1 {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("⬅",
+			fmt.Sprintf("season:%d:%d:%d", movieID, seasonNumber, page-1)))
+	}
+	if page < pages {
+		nav = append(nav, tgbotapi.NewInlineKeyboardButtonData("➡",
+			fmt.Sprintf("season:%d:%d:%d", movieID, seasonNumber, page+1)))
+	}
+	rows := [][]tgbotapi.InlineKeyboardButton{}
+	if len(nav) > 0 {
+		rows = append(rows, nav)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr(lang, "seasons.back"), "seasons:"+strconv.Itoa(movieID)),
+	))
+
+	b.sendOrEdit(chatID, messageID, text, tgbotapi.NewInlineKeyboardMarkup(rows...))
+}
+
+// sendOrEdit replaces the text of a bot message, or sends a new one when the
+// message can't be edited (for example, a photo card).
+func (b *Bot) sendOrEdit(chatID int64, messageID int, text string, keyboard tgbotapi.InlineKeyboardMarkup) {
+	if messageID != 0 {
+		edit := tgbotapi.NewEditMessageTextAndMarkup(chatID, messageID, text, keyboard)
+		edit.ParseMode = "HTML"
+		edit.DisableWebPagePreview = true
+		if _, err := b.sender.Send(edit); err == nil {
+			return
+		}
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "HTML"
+	msg.DisableWebPagePreview = true
+	msg.ReplyMarkup = keyboard
+	if _, err := b.sender.Send(msg); err != nil {
+		slog.Error("Error sending message", "error", err)
+	}
+}
@@ -0,0 +1,242 @@
+package bot
+
+import (
+	"errors"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"log/slog"
+	"net/http"
+	"reflect"
+	"sync"
+	"time"
+)
+
+// Telegram allows about 30 messages per second overall, about one per second
+// in a private chat and 20 per minute in a group. Short bursts are tolerated,
+// which lets a results page go out without delays.
+const (
+	globalSendInterval  = time.Second / 30
+	globalSendBurst     = 30
+	privateSendInterval = time.Second
+	groupSendInterval   = 3 * time.Second
+	chatSendBurst       = 8
+	sendMaxRetries      = 3
+	// limiterSweepInterval is how often limiters of idle chats are dropped
+	limiterSweepInterval = time.Minute
+)
+
+// sender wraps the Telegram API calls of the bot. Calls to a chat are made
+// by the chat's own goroutine in the order they were issued, within the
+// global limit and, for new messages, the per-chat one, and calls rejected with 429 are retried there
+// after the delay Telegram asks for. Waiting for one chat never delays the
+// calls to other chats.
+type sender struct {
+	api     *tgbotapi.BotAPI
+	global  *rateLimiter
+	workers *chatWorkers
+
+	privateInterval time.Duration
+	groupInterval   time.Duration
+	chatBurst       int
+	// retryAfterUnit is the unit of the delay in 429 responses
+	retryAfterUnit time.Duration
+
+	mu        sync.Mutex
+	limiters  map[int64]*rateLimiter
+	lastSweep time.Time
+}
+
+func newSender(api *tgbotapi.BotAPI) *sender {
+	return &sender{
+		api:             api,
+		global:          newRateLimiter(globalSendInterval, globalSendBurst),
+		workers:         newChatWorkers(),
+		privateInterval: privateSendInterval,
+		groupInterval:   groupSendInterval,
+		chatBurst:       chatSendBurst,
+		retryAfterUnit:  time.Second,
+		limiters:        make(map[int64]*rateLimiter),
+		lastSweep:       time.Now(),
+	}
+}
+
+func (s *sender) Send(c tgbotapi.Chattable) (tgbotapi.Message, error) {
+	var msg tgbotapi.Message
+	err := s.do(c, func() error {
+		var err error
+		msg, err = s.api.Send(c)
+		return err
+	})
+	return msg, err
+}
+
+func (s *sender) Request(c tgbotapi.Chattable) (*tgbotapi.APIResponse, error) {
+	var resp *tgbotapi.APIResponse
+	err := s.do(c, func() error {
+		var err error
+		resp, err = s.api.Request(c)
+		return err
+	})
+	return resp, err
+}
+
+func (s *sender) SendMediaGroup(config tgbotapi.MediaGroupConfig) ([]tgbotapi.Message, error) {
+	var msgs []tgbotapi.Message
+	err := s.do(config, func() error {
+		var err error
+		msgs, err = s.api.SendMediaGroup(config)
+		return err
+	})
+	return msgs, err
+}
+
+func (s *sender) StopPoll(config tgbotapi.StopPollConfig) (tgbotapi.Poll, error) {
+	var poll tgbotapi.Poll
+	err := s.do(config, func() error {
+		var err error
+		poll, err = s.api.StopPoll(config)
+		return err
+	})
+	return poll, err
+}
+
+// do runs call in the goroutine of the chat c is addressed to and waits for
+// its result. Calls without a chat, such as callback answers, don't count
+// towards the limits and are made right away. Only calls that post a new
+// message count towards the chat's limit.
+func (s *sender) do(c tgbotapi.Chattable, call func() error) error {
+	chatID := chatIDOf(c)
+	if chatID == 0 {
+		return s.withRetry(call)
+	}
+
+	done := make(chan error, 1)
+	s.enqueue(chatID, postsMessage(c), func() {
+		done <- s.withRetry(call)
+	})
+	return <-done
+}
+
+// enqueue queues call for the chat's goroutine, which runs it once the global
+// limit and, if perChat is set, the chat's limit allow.
+func (s *sender) enqueue(chatID int64, perChat bool, call func()) {
+	s.workers.Go(chatID, func() {
+		if perChat {
+			s.chatLimiter(chatID).wait()
+		}
+		s.global.wait()
+		call()
+	})
+}
+
+// chatLimiter returns the chat's limiter. Limiters that have recovered their
+// whole burst behave like new ones, so they are dropped from time to time;
+// one that is still recovering is kept, which takes up to burst intervals
+// after the chat's last call.
+func (s *sender) chatLimiter(chatID int64) *rateLimiter {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	if now.Sub(s.lastSweep) >= limiterSweepInterval {
+		for id, limiter := range s.limiters {
+			if limiter.idle(now) {
+				delete(s.limiters, id)
+			}
+		}
+		s.lastSweep = now
+	}
+
+	limiter, ok := s.limiters[chatID]
+	if !ok {
+		interval := s.privateInterval
+		if chatID < 0 {
+			interval = s.groupInterval
+		}
+		limiter = newRateLimiter(interval, s.chatBurst)
+		s.limiters[chatID] = limiter
+	}
+	return limiter
+}
+
+// withRetry calls again after the delay Telegram asks for when it answers
+// 429 Too Many Requests.
+func (s *sender) withRetry(call func() error) error {
+	for attempt := 0; ; attempt++ {
+		err := call()
+		var tgErr *tgbotapi.Error
+		if attempt == sendMaxRetries || !errors.As(err, &tgErr) ||
+			tgErr.Code != http.StatusTooManyRequests || tgErr.RetryAfter <= 0 {
+			return err
+		}
+		slog.Warn("Telegram rate limit hit, retrying", "retry_after", tgErr.RetryAfter, "attempt", attempt+1)
+		time.Sleep(time.Duration(tgErr.RetryAfter) * s.retryAfterUnit)
+	}
+}
+
+// chatIDOf returns the chat a request is addressed to, or 0 for requests
+// without one such as callback answers. Request configs carry it in a ChatID
+// field of their own or of the embedded BaseChat or BaseEdit.
+func chatIDOf(c tgbotapi.Chattable) int64 {
+	value := reflect.Indirect(reflect.ValueOf(c))
+	if value.Kind() != reflect.Struct {
+		return 0
+	}
+	field := value.FieldByName("ChatID")
+	if !field.IsValid() || field.Kind() != reflect.Int64 {
+		return 0
+	}
+	return field.Int()
+}
+
+// postsMessage reports whether c posts a new message to the chat. Edits,
+// deletions, chat actions and stopping polls don't.
+func postsMessage(c tgbotapi.Chattable) bool {
+	switch c.(type) {
+	case tgbotapi.MessageConfig, tgbotapi.ForwardConfig, tgbotapi.CopyMessageConfig,
+		tgbotapi.PhotoConfig, tgbotapi.AudioConfig, tgbotapi.DocumentConfig,
+		tgbotapi.StickerConfig, tgbotapi.VideoConfig, tgbotapi.AnimationConfig,
+		tgbotapi.VideoNoteConfig, tgbotapi.VoiceConfig, tgbotapi.LocationConfig,
+		tgbotapi.VenueConfig, tgbotapi.ContactConfig, tgbotapi.SendPollConfig,
+		tgbotapi.DiceConfig, tgbotapi.GameConfig, tgbotapi.InvoiceConfig,
+		tgbotapi.MediaGroupConfig:
+		return true
+	}
+	return false
+}
+
+// rateLimiter spaces events interval apart while allowing bursts of up to
+// burst events.
+type rateLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	burst    int
+	// next is when the next event would be due if events were evenly spaced
+	next time.Time
+}
+
+func newRateLimiter(interval time.Duration, burst int) *rateLimiter {
+	return &rateLimiter{interval: interval, burst: burst}
+}
+
+// idle reports whether the limiter has recovered its whole burst by now.
+func (l *rateLimiter) idle(now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return !l.next.After(now)
+}
+
+// wait blocks until another event fits the limit.
+func (l *rateLimiter) wait() {
+	l.mu.Lock()
+	now := time.Now()
+	if l.next.Before(now) {
+		l.next = now
+	}
+	allowedAt := l.next.Add(-time.Duration(l.burst-1) * l.interval)
+	l.next = l.next.Add(l.interval)
+	l.mu.Unlock()
+
+	if delay := allowedAt.Sub(now); delay > 0 {
+		time.Sleep(delay)
+	}
+}
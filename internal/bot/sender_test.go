@@ -0,0 +1,216 @@
+package bot
+
+import (
+	"errors"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"net/http"
+	"sync"
+	"testing"
+	"time"
+)
+
+// newTestSender returns a sender without an API client and with short limits.
+func newTestSender() *sender {
+	s := newSender(nil)
+	s.global = newRateLimiter(time.Millisecond, 100)
+	s.privateInterval = 10 * time.Millisecond
+	s.groupInterval = 10 * time.Millisecond
+	s.chatBurst = 3
+	s.retryAfterUnit = 50 * time.Millisecond
+	return s
+}
+
+func TestRateLimiterBurst(t *testing.T) {
+	const interval = 50 * time.Millisecond
+	limiter := newRateLimiter(interval, 3)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		limiter.wait()
+	}
+	if elapsed := time.Since(start); elapsed > interval/2 {
+		t.Fatalf("burst of 3 took %v, want no delay", elapsed)
+	}
+}
+
+func TestRateLimiterSpacing(t *testing.T) {
+	const interval = 50 * time.Millisecond
+	limiter := newRateLimiter(interval, 2)
+
+	start := time.Now()
+	var times []time.Duration
+	for i := 0; i < 5; i++ {
+		limiter.wait()
+		times = append(times, time.Since(start))
+	}
+	// Первые два события проходят сразу, дальше по одному за интервал
+	for i, want := range []time.Duration{0, 0, interval, 2 * interval, 3 * interval} {
+		if times[i] < want-5*time.Millisecond || times[i] > want+interval/2 {
+			t.Errorf("event %d at %v, want about %v", i, times[i], want)
+		}
+	}
+}
+
+func TestRateLimiterIdle(t *testing.T) {
+	const interval = 20 * time.Millisecond
+	limiter := newRateLimiter(interval, 3)
+	if !limiter.idle(time.Now()) {
+		t.Fatal("new limiter is not idle")
+	}
+
+	for i := 0; i < 3; i++ {
+		limiter.wait()
+	}
+	now := time.Now()
+	if limiter.idle(now) {
+		t.Fatal("limiter is idle right after a burst")
+	}
+	if !limiter.idle(now.Add(3 * interval)) {
+		t.Fatal("limiter is not idle after burst intervals")
+	}
+}
+
+func TestSenderKeepsChatLimiter(t *testing.T) {
+	s := newTestSender()
+	s.chatBurst = 2
+	s.privateInterval = 50 * time.Millisecond
+	msg := tgbotapi.NewMessage(1, "test")
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := s.do(msg, func() error { return nil }); err != nil {
+			t.Fatalf("do: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < s.privateInterval-5*time.Millisecond {
+		t.Fatalf("3 calls with a burst of 2 took %v, want at least %v", elapsed, s.privateInterval)
+	}
+}
+
+func TestSenderEditsSkipChatLimiter(t *testing.T) {
+	s := newTestSender()
+	s.chatBurst = 1
+	s.privateInterval = time.Second
+	calls := []tgbotapi.Chattable{
+		tgbotapi.NewMessage(1, "test"),
+		tgbotapi.NewEditMessageText(1, 1, "edited"),
+		tgbotapi.NewChatAction(1, tgbotapi.ChatTyping),
+		tgbotapi.NewDeleteMessage(1, 1),
+		tgbotapi.NewStopPoll(1, 1),
+	}
+
+	start := time.Now()
+	for _, c := range calls {
+		if err := s.do(c, func() error { return nil }); err != nil {
+			t.Fatalf("do: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed > s.privateInterval/2 {
+		t.Fatalf("calls after a message took %v, want no chat delay", elapsed)
+	}
+}
+
+func TestSenderKeepsChatOrder(t *testing.T) {
+	s := newTestSender()
+	s.chatBurst = 100
+
+	const calls = 50
+	var (
+		mu    sync.Mutex
+		order []int
+		wg    sync.WaitGroup
+	)
+	wg.Add(calls)
+	for i := 0; i < calls; i++ {
+		s.enqueue(42, true, func() {
+			defer wg.Done()
+			mu.Lock()
+			order = append(order, i)
+			mu.Unlock()
+		})
+	}
+	wg.Wait()
+
+	for i, got := range order {
+		if got != i {
+			t.Fatalf("position %d ran call %d, order: %v", i, got, order)
+		}
+	}
+}
+
+func TestSenderRetriesTooManyRequests(t *testing.T) {
+	s := newTestSender()
+	msg := tgbotapi.NewMessage(1, "test")
+
+	attempts := 0
+	start := time.Now()
+	err := s.do(msg, func() error {
+		attempts++
+		if attempts == 1 {
+			return &tgbotapi.Error{
+				Code:               http.StatusTooManyRequests,
+				Message:            "Too Many Requests: retry after 1",
+				ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 1},
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if attempts != 2 {
+		t.Fatalf("attempts = %d, want 2", attempts)
+	}
+	if elapsed := time.Since(start); elapsed < s.retryAfterUnit {
+		t.Fatalf("retried after %v, want at least %v", elapsed, s.retryAfterUnit)
+	}
+}
+
+func TestSenderGivesUpAfterRetries(t *testing.T) {
+	s := newTestSender()
+	s.retryAfterUnit = time.Millisecond
+	tooMany := &tgbotapi.Error{
+		Code:               http.StatusTooManyRequests,
+		ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 1},
+	}
+
+	attempts := 0
+	err := s.do(tgbotapi.NewMessage(1, "test"), func() error {
+		attempts++
+		return tooMany
+	})
+	if !errors.Is(err, tooMany) {
+		t.Fatalf("err = %v, want %v", err, tooMany)
+	}
+	if attempts != sendMaxRetries+1 {
+		t.Fatalf("attempts = %d, want %d", attempts, sendMaxRetries+1)
+	}
+}
+
+func TestSenderRetryDoesNotBlockOtherChats(t *testing.T) {
+	s := newTestSender()
+	s.retryAfterUnit = time.Second
+
+	retrying := make(chan struct{})
+	go s.do(tgbotapi.NewMessage(1, "test"), func() error {
+		select {
+		case <-retrying:
+			return nil
+		default:
+			close(retrying)
+			return &tgbotapi.Error{
+				Code:               http.StatusTooManyRequests,
+				ResponseParameters: tgbotapi.ResponseParameters{RetryAfter: 1},
+			}
+		}
+	})
+	<-retrying
+
+	start := time.Now()
+	if err := s.do(tgbotapi.NewMessage(2, "test"), func() error { return nil }); err != nil {
+		t.Fatalf("do: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > s.retryAfterUnit/2 {
+		t.Fatalf("call to another chat took %v while the first one was retrying", elapsed)
+	}
+}
@@ -10,16 +10,28 @@ import (
 	"time"
 )
 
-func (b *Bot) sendStateExpired(chatID int64) {
-	msg := tgbotapi.NewMessage(chatID, "Сессия поиска истекла. Пожалуйста, начните поиск заново.")
-	msg.ReplyMarkup = b.createMainMenuKeyboard()
-	_, err := b.api.Send(msg)
+func (b *Bot) sendText(chatID int64, text string) {
+	_, err := b.sender.Send(tgbotapi.NewMessage(chatID, text))
+	if err != nil {
+		slog.Error("Error sending message", "error", err)
+	}
+}
+
+func (b *Bot) sendStateExpired(chatID int64, lang string) {
+	msg := tgbotapi.NewMessage(chatID, tr(lang, "session.expired"))
+	msg.ReplyMarkup = b.createMainMenuKeyboard(lang)
+	_, err := b.sender.Send(msg)
 	if err != nil {
 		slog.Error("Error sending session expired message", "error", err)
 	}
 }
 
-func (b *Bot) sendMovies(chatID int64, movies []model.Movie, page int, paginationPrefix string) {
+// sendMovies sends a page of movies in the user's layout, leaving out movies
+// hidden by the adult content filter, and then prefetches the next page with
+// next. extraRows are added to the pagination keyboard, above the page buttons.
+func (b *Bot) sendMovies(chatID int64, settings model.Settings, movies []model.Movie, sessionID string, page int, limit int,
+	paginationPrefix string, next pageLoader, extraRows ...[]tgbotapi.InlineKeyboardButton) {
+	lang := settings.Language
 	start := time.Now()
 	defer func() {
 		slog.Debug("sendMovies executed",
@@ -28,31 +40,58 @@ func (b *Bot) sendMovies(chatID int64, movies []model.Movie, page int, paginatio
 	}()
 
 	if len(movies) == 0 {
-		b.sendNoMoviesFound(chatID)
+		b.sendNoMoviesFound(chatID, lang)
+		return
+	}
+	// Подгрузка начинается после отправки страницы, чтобы не отнимать у нее запросы
+	defer b.prefetchNextPage(chatID, settings, len(movies), limit, next)
+	movies = visibleMovies(settings, movies)
+	if len(movies) == 0 {
+		// Следующие страницы могут быть не пустыми, поэтому кнопки листания остаются
+		b.sendAllHidden(chatID, lang, sessionID, page, paginationPrefix, extraRows...)
 		return
 	}
 
-	tempMsg := b.sendTempMessage(chatID, "⏳ Подготавливаю постеры..")
+	switch settings.Layout {
+	case layoutCompact:
+		b.sendCompactMovies(chatID, lang, movies, sessionID, page, paginationPrefix, extraRows...)
+		return
+	case layoutCollage:
+		b.sendCollageMovies(chatID, lang, movies, sessionID, page, paginationPrefix, extraRows...)
+		return
+	}
+
+	tempMsg := b.sendTempMessage(chatID, tr(lang, "movies.preparing_posters"))
 	posters := b.loadPostersConcurrently(movies)
-	mediaGroup := b.createMediaGroup(movies, posters)
+	mediaGroup := b.createMediaGroup(lang, movies, posters)
 
 	b.cleanupTempMessage(chatID, tempMsg)
 	b.sendChatAction(chatID, tgbotapi.ChatUploadPhoto)
-	b.sendMediaGroupOrFallback(chatID, mediaGroup, movies)
+	b.sendMediaGroupOrFallback(chatID, mediaGroup, posters)
 	b.sendMoviesDescription(chatID, movies)
-	b.sendPagination(chatID, page, paginationPrefix)
+	b.sendPagination(chatID, lang, movies, sessionID, page, paginationPrefix, extraRows...)
 }
 
-func (b *Bot) sendNoMoviesFound(chatID int64) {
-	msg := tgbotapi.NewMessage(chatID, "Фильмы не найдены")
-	_, err := b.api.Send(msg)
+func (b *Bot) sendNoMoviesFound(chatID int64, lang string) {
+	msg := tgbotapi.NewMessage(chatID, tr(lang, "movies.not_found"))
+	_, err := b.sender.Send(msg)
 	if err != nil {
 		slog.Error("Error sending no movies found message", "error", err)
 	}
 }
 
+func (b *Bot) sendAllHidden(chatID int64, lang string, sessionID string, page int, paginationPrefix string,
+	extraRows ...[]tgbotapi.InlineKeyboardButton) {
+	msg := tgbotapi.NewMessage(chatID, tr(lang, "movies.all_hidden"))
+	msg.ReplyMarkup = b.createMoviesKeyboard(chatID, nil, sessionID, page, paginationPrefix, extraRows...)
+	_, err := b.sender.Send(msg)
+	if err != nil {
+		slog.Error("Error sending hidden movies message", "error", err)
+	}
+}
+
 func (b *Bot) sendTempMessage(chatID int64, text string) tgbotapi.Message {
-	tempMsg, err := b.api.Send(tgbotapi.NewMessage(chatID, text))
+	tempMsg, err := b.sender.Send(tgbotapi.NewMessage(chatID, text))
 	if err != nil {
 		slog.Error("Error sending temp message", "error", err)
 	}
@@ -89,15 +128,11 @@ func (b *Bot) loadPostersConcurrently(movies []model.Movie) []tgbotapi.RequestFi
 	return posters
 }
 
-func (b *Bot) createMediaGroup(movies []model.Movie, posters []tgbotapi.RequestFileData) []interface{} {
+func (b *Bot) createMediaGroup(lang string, movies []model.Movie, posters []tgbotapi.RequestFileData) []interface{} {
 	var mediaGroup []interface{}
 	for i, movie := range movies {
 		photo := tgbotapi.NewInputMediaPhoto(posters[i])
-		caption := formatMovieCaption(movie)
-		if len(caption) > telegramCaptionLimit {
-			caption = caption[:telegramCaptionLimit-3] + "..."
-		}
-		photo.Caption = caption
+		photo.Caption = formatMovieCaption(lang, movie)
 		mediaGroup = append(mediaGroup, photo)
 	}
 	return mediaGroup
@@ -107,124 +142,187 @@ func (b *Bot) cleanupTempMessage(chatID int64, tempMsg tgbotapi.Message) {
 	if tempMsg.MessageID == 0 {
 		return
 	}
-	_, err := b.api.Request(tgbotapi.NewDeleteMessage(chatID, tempMsg.MessageID))
+	_, err := b.sender.Request(tgbotapi.NewDeleteMessage(chatID, tempMsg.MessageID))
 	if err != nil {
 		slog.Error("Error deleting temp message", "error", err)
 	}
 }
 
 func (b *Bot) sendChatAction(chatID int64, action string) {
-	_, err := b.api.Request(tgbotapi.NewChatAction(chatID, action))
+	_, err := b.sender.Request(tgbotapi.NewChatAction(chatID, action))
 	if err != nil {
 		slog.Error("Error sending chat action", "action", action, "error", err)
 	}
 }
 
-func (b *Bot) sendMediaGroupOrFallback(chatID int64, mediaGroup []interface{}, movies []model.Movie) {
-	_, err := b.api.SendMediaGroup(tgbotapi.MediaGroupConfig{
+// sendMediaGroupOrFallback sends the posters as a media group. If Telegram
+// rejects it, the posters go out as one collage instead of a message per
+// movie, the numbered list that follows describes them either way.
+func (b *Bot) sendMediaGroupOrFallback(chatID int64, mediaGroup []interface{}, posters []tgbotapi.RequestFileData) {
+	_, err := b.sender.SendMediaGroup(tgbotapi.MediaGroupConfig{
 		ChatID: chatID,
 		Media:  mediaGroup,
 	})
+	if err == nil {
+		return
+	}
+	slog.Error("SendMediaGroup error:", "error", err)
+
+	collage, err := buildCollage(posters)
 	if err != nil {
-		slog.Error("SendMediaGroup error:", "error", err)
-		for i, movie := range movies {
-			b.sendSingleMovie(chatID, movie, i+1)
-		}
+		slog.Error("Error building fallback collage", "error", err)
+		return
+	}
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: "collage.jpg", Bytes: collage})
+	if _, err := b.sender.Send(photo); err != nil {
+		slog.Error("Error sending fallback collage", "error", err)
 	}
 }
 
 func (b *Bot) sendMoviesDescription(chatID int64, movies []model.Movie) {
 	b.sendChatAction(chatID, tgbotapi.ChatTyping)
 
-	var description string
-	for i, movie := range movies {
-		description += fmt.Sprintf("%d. %s\n", i+1, formatMovieDescription(movie))
-	}
+	description := formatMoviesDescription(movies)
 
 	msg := tgbotapi.NewMessage(chatID, description)
 	msg.ParseMode = "HTML"
 	msg.DisableWebPagePreview = true
-	_, err := b.api.Send(msg)
+	_, err := b.sender.Send(msg)
 	if err != nil {
 		slog.Error("Error sending description", "error", err)
 	}
 }
 
-func (b *Bot) sendPersons(chatID int64, persons []model.Person, page int) {
+func (b *Bot) sendPersons(chatID int64, lang string, persons []model.Person, sessionID string, page int) {
 	if len(persons) == 0 {
-		b.sendNoPersonsFound(chatID)
+		b.sendNoPersonsFound(chatID, lang)
 		return
 	}
 
-	text := "Результаты поиска актеров/режиссеров:\n\n"
+	text := tr(lang, "persons.results") + "\n\n"
 	for i, person := range persons {
 		text += fmt.Sprintf("%d. %s\n", i+1, formatPersonDescription(person))
 	}
 
-	keyboard := b.createPersonsKeyboard(persons, page)
+	keyboard := b.createPersonsKeyboard(persons, sessionID, page)
 	msg := tgbotapi.NewMessage(chatID, text)
 	msg.ReplyMarkup = keyboard
-	_, err := b.api.Send(msg)
+	_, err := b.sender.Send(msg)
 	if err != nil {
 		slog.Error("SendMessage err:", "error", err)
 	}
 }
 
-func (b *Bot) sendNoPersonsFound(chatID int64) {
-	msg := tgbotapi.NewMessage(chatID, "Актеры/режиссеры не найдены")
-	_, err := b.api.Send(msg)
+func (b *Bot) sendNoPersonsFound(chatID int64, lang string) {
+	msg := tgbotapi.NewMessage(chatID, tr(lang, "persons.not_found"))
+	_, err := b.sender.Send(msg)
 	if err != nil {
 		slog.Error("Error sending no persons found message", "error", err)
 	}
 }
 
-func (b *Bot) createPersonsKeyboard(persons []model.Person, page int) tgbotapi.InlineKeyboardMarkup {
+func (b *Bot) createPersonsKeyboard(persons []model.Person, sessionID string, page int) tgbotapi.InlineKeyboardMarkup {
 	var buttons []tgbotapi.InlineKeyboardButton
 	for _, person := range persons {
+		name := person.Name
+		if name == "" {
+			name = person.EnName
+		}
 		btn := tgbotapi.NewInlineKeyboardButtonData(
-			person.Name,
-			"person_select:"+strconv.Itoa(person.Id),
+			name,
+			"person_select:"+sessionID+":"+strconv.Itoa(person.Id),
 		)
 		buttons = append(buttons, btn)
 	}
 
-	paginationRow := b.createPersonPaginationRow(page)
+	paginationRow := b.createPersonPaginationRow(sessionID, page)
 	return tgbotapi.NewInlineKeyboardMarkup(
 		tgbotapi.NewInlineKeyboardRow(buttons...),
 		paginationRow,
 	)
 }
 
-func (b *Bot) sendPagination(chatID int64, page int, prefix string) {
-	buttons := []tgbotapi.InlineKeyboardButton{}
-	if page > 1 {
-		buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("⬅", prefix+":"+strconv.Itoa(page-1)))
+func (b *Bot) sendPagination(chatID int64, lang string, movies []model.Movie, sessionID string, page int, prefix string,
+	extraRows ...[]tgbotapi.InlineKeyboardButton) {
+	msg := tgbotapi.NewMessage(chatID, tr(lang, "pagination.page", page))
+	msg.ReplyMarkup = b.createMoviesKeyboard(chatID, movies, sessionID, page, prefix, extraRows...)
+	_, err := b.sender.Send(msg)
+	if err != nil {
+		slog.Error("Send pagination buttons err:", "error", err)
 	}
-	buttons = append(buttons, tgbotapi.NewInlineKeyboardButtonData("➡", prefix+":"+strconv.Itoa(page+1)))
+}
 
-	msg := tgbotapi.NewMessage(chatID, "Страница: "+strconv.Itoa(page))
-	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(buttons)
-	_, err := b.api.Send(msg)
+// sendCollageMovies sends a page of movies as one collage of their posters,
+// captioned with the numbered list and carrying the page buttons. When the list
+// doesn't fit into a caption it is sent as separate messages.
+func (b *Bot) sendCollageMovies(chatID int64, lang string, movies []model.Movie, sessionID string, page int, prefix string,
+	extraRows ...[]tgbotapi.InlineKeyboardButton) {
+	tempMsg := b.sendTempMessage(chatID, tr(lang, "movies.preparing_posters"))
+	collage, err := buildCollage(b.loadPostersConcurrently(movies))
+	b.cleanupTempMessage(chatID, tempMsg)
 	if err != nil {
-		slog.Error("Send pagination buttons err:", "error", err)
+		slog.Error("Error building collage", "error", err)
+		b.sendCompactMovies(chatID, lang, movies, sessionID, page, prefix, extraRows...)
+		return
+	}
+
+	b.sendChatAction(chatID, tgbotapi.ChatUploadPhoto)
+	photo := tgbotapi.NewPhoto(chatID, tgbotapi.FileBytes{Name: "collage.jpg", Bytes: collage})
+	caption := formatMoviesDescription(movies)
+	captionFits := len([]rune(htmlTagPattern.ReplaceAllString(caption, ""))) <= telegramCaptionLimit
+	if captionFits {
+		photo.Caption = caption
+		photo.ParseMode = "HTML"
+		photo.ReplyMarkup = b.createMoviesKeyboard(chatID, movies, sessionID, page, prefix, extraRows...)
+	}
+	if _, err := b.sender.Send(photo); err != nil {
+		slog.Error("Error sending collage", "error", err)
+	}
+	if !captionFits {
+		b.sendMoviesDescription(chatID, movies)
+		b.sendPagination(chatID, lang, movies, sessionID, page, prefix, extraRows...)
+	}
+}
+
+// sendCompactMovies sends a page of movies as a single text message with the
+// numbered list and its buttons. Posters aren't downloaded at all.
+func (b *Bot) sendCompactMovies(chatID int64, lang string, movies []model.Movie, sessionID string, page int, prefix string,
+	extraRows ...[]tgbotapi.InlineKeyboardButton) {
+	text := tr(lang, "pagination.page", page) + "\n\n"
+	for i, movie := range movies {
+		text += fmt.Sprintf("%d. %s\n", i+1, formatCompactMovie(lang, movie))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "HTML"
+	msg.DisableWebPagePreview = true
+	msg.ReplyMarkup = b.createMoviesKeyboard(chatID, movies, sessionID, page, prefix, extraRows...)
+	if _, err := b.sender.Send(msg); err != nil {
+		slog.Error("Error sending compact movies", "error", err)
 	}
 }
 
-func (b *Bot) sendSingleMovie(chatID int64, movie model.Movie, index int) {
-	poster := GetSafePoster(movie.Poster)
-	photoMsg := tgbotapi.NewPhoto(chatID, poster)
-	photoMsg.Caption = formatMovieCaption(movie)
-	_, err := b.api.Send(photoMsg)
+// sendMovieCard sends a movie's poster and description with its action buttons.
+// extraRows are placed above the common card buttons.
+func (b *Bot) sendMovieCard(chatID int64, lang string, movie model.Movie, extraRows ...[]tgbotapi.InlineKeyboardButton) {
+	keyboard := b.createMovieCardKeyboard(lang, movie)
+	keyboard.InlineKeyboard = append(extraRows, keyboard.InlineKeyboard...)
+
+	photo := tgbotapi.NewPhoto(chatID, GetSafePoster(movie.Poster))
+	photo.Caption = formatMovieCaption(lang, movie)
+	photo.ReplyMarkup = keyboard
+	_, err := b.sender.Send(photo)
 	if err != nil {
-		slog.Error("Failed to send movie poster", "movie", movie.Title, "error", err)
+		slog.Error("Failed to send movie card", "movie", movie.Title, "error", err)
 	}
+}
 
-	text := fmt.Sprintf("%d. %s", index, formatMovieDescription(movie))
-	textMsg := tgbotapi.NewMessage(chatID, text)
-	textMsg.ParseMode = "HTML"
-	textMsg.DisableWebPagePreview = true
-	_, err = b.api.Send(textMsg)
+func (b *Bot) sendPersonCard(chatID int64, lang string, person model.Person) {
+	photo := tgbotapi.NewPhoto(chatID, GetSafePoster(person.Photo))
+	photo.Caption = formatPersonCard(lang, person)
+	photo.ReplyMarkup = b.createPersonCardKeyboard(lang, person)
+	_, err := b.sender.Send(photo)
 	if err != nil {
-		slog.Error("Failed to send movie description", "movie", movie.Title, "error", err)
+		slog.Error("Failed to send person card", "person", person.Name, "error", err)
 	}
 }
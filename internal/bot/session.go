@@ -0,0 +1,39 @@
+package bot
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"kinopoisk-bot/internal/model"
+	"log/slog"
+	"strconv"
+)
+
+// newSessionID returns a short random identifier that keeps a search's
+// callbacks apart from other searches in the same chat. It has to stay short
+// because Telegram limits callback data to 64 bytes.
+func newSessionID() string {
+	buf := make([]byte, 4)
+	if _, err := rand.Read(buf); err != nil {
+		slog.Error("Error generating session id", "error", err)
+	}
+	return hex.EncodeToString(buf)
+}
+
+// startSession stores a new search session and returns it.
+func (b *Bot) startSession(chatID int64, state model.SearchState) (*model.SearchState, error) {
+	state.SessionID = newSessionID()
+	if state.Page == 0 {
+		state.Page = 1
+	}
+	if err := b.redis.SaveSession(chatID, state); err != nil {
+		return nil, err
+	}
+	if err := b.redis.IncrSearches(state.Type); err != nil {
+		slog.Error("Error counting search in Redis", "error", err)
+	}
+	return &state, nil
+}
+
+func pageCallbackData(prefix, sessionID string, page int) string {
+	return prefix + ":" + sessionID + ":" + strconv.Itoa(page)
+}
@@ -0,0 +1,183 @@
+package bot
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"kinopoisk-bot/internal/i18n"
+	"kinopoisk-bot/internal/model"
+	"log/slog"
+	"slices"
+)
+
+// adultAgeRating is the age rating from which movies are hidden by the adult
+// content filter.
+const adultAgeRating = 18
+
+// Result page layouts. Their labels are the "layout.<layout>" messages.
+const (
+	// layoutMedia sends a media group of posters, a numbered list and a
+	// pagination message.
+	layoutMedia = "media"
+	// layoutCompact sends a single text message without posters, for slow
+	// connections.
+	layoutCompact = "compact"
+	// layoutCollage sends one numbered grid image of the posters captioned
+	// with the numbered list.
+	layoutCollage = "collage"
+)
+
+var layouts = []string{layoutMedia, layoutCompact, layoutCollage}
+
+// resultsPerPageOptions are the page sizes a user can choose from. Telegram
+// media groups hold at most 10 photos, which caps the page size.
+var resultsPerPageOptions = []int{3, 5, 10}
+
+func defaultSettings() model.Settings {
+	return model.Settings{
+		ResultsPerPage:      defaultResultsPerPage,
+		Layout:              layoutMedia,
+		TitleMode:           titleModeRussian,
+		NotifyAnnouncements: true,
+	}
+}
+
+// loadSettings returns the user's stored settings. Language stays empty when
+// it follows the user's Telegram language.
+func (b *Bot) loadSettings(userID int64) model.Settings {
+	settings, err := b.redis.GetSettings(userID, defaultSettings())
+	if err != nil {
+		slog.Error("Error getting settings from Redis", "error", err)
+	}
+	// Отключенные постеры из старых настроек соответствуют компактному виду
+	if settings.Posters != nil {
+		if !*settings.Posters {
+			settings.Layout = layoutCompact
+		}
+		settings.Posters = nil
+	}
+	return settings
+}
+
+// userSettings returns the settings to serve the user with, with the UI
+// language resolved.
+func (b *Bot) userSettings(user *tgbotapi.User) model.Settings {
+	if user == nil {
+		settings := defaultSettings()
+		settings.Language = i18n.DefaultLocale
+		return settings
+	}
+	settings := b.loadSettings(user.ID)
+	if !i18n.IsSupported(settings.Language) {
+		settings.Language = i18n.Resolve(user.LanguageCode)
+	}
+	return settings
+}
+
+// visibleMovies drops movies hidden by the user's adult content filter.
+func visibleMovies(settings model.Settings, movies []model.Movie) []model.Movie {
+	if !settings.HideAdult {
+		return movies
+	}
+	var visible []model.Movie
+	for _, movie := range movies {
+		if movie.AgeRating < adultAgeRating {
+			visible = append(visible, movie)
+		}
+	}
+	return visible
+}
+
+// pageSize returns the number of results per page of a search session.
+// Sessions started before page sizes were configurable use the default.
+func pageSize(state *model.SearchState) int {
+	if state.Limit <= 0 {
+		return defaultResultsPerPage
+	}
+	return state.Limit
+}
+
+func (b *Bot) handleSettingsCommand(chatID int64, user *tgbotapi.User) {
+	lang := b.userSettings(user).Language
+	msg := tgbotapi.NewMessage(chatID, tr(lang, "settings.title"))
+	msg.ReplyMarkup = createSettingsKeyboard(lang, b.loadSettings(user.ID))
+	if _, err := b.sender.Send(msg); err != nil {
+		slog.Error("Error sending settings menu", "error", err)
+	}
+}
+
+// handleSettingsChange applies a "settings:<field>" callback and redraws the
+// menu in place. Toggles flip, multiple-choice settings cycle through their
+// options.
+func (b *Bot) handleSettingsChange(query *tgbotapi.CallbackQuery, field string) {
+	settings := b.loadSettings(query.From.ID)
+	switch field {
+	case "page":
+		settings.ResultsPerPage = nextOption(resultsPerPageOptions, settings.ResultsPerPage)
+	case "layout":
+		settings.Layout = nextOption(layouts, settings.Layout)
+	case "language":
+		locales := []string{""}
+		for _, locale := range i18n.Locales {
+			locales = append(locales, locale.Code)
+		}
+		settings.Language = nextOption(locales, settings.Language)
+	case "titles":
+		settings.TitleMode = nextOption(titleModes, settings.TitleMode)
+	case "adult":
+		settings.HideAdult = !settings.HideAdult
+	case "announcements":
+		settings.NotifyAnnouncements = !settings.NotifyAnnouncements
+	case "movienights":
+		settings.NotifyMovieNights = !settings.NotifyMovieNights
+	default:
+		slog.Warn("Unknown setting", "field", field)
+		return
+	}
+
+	if err := b.redis.SaveSettings(query.From.ID, settings); err != nil {
+		slog.Error("Error saving settings to Redis", "error", err)
+		return
+	}
+
+	lang := b.userSettings(query.From).Language
+	edit := tgbotapi.NewEditMessageTextAndMarkup(query.Message.Chat.ID, query.Message.MessageID,
+		tr(lang, "settings.title"), createSettingsKeyboard(lang, settings))
+	if _, err := b.sender.Send(edit); err != nil {
+		slog.Error("Error editing settings menu", "error", err)
+	}
+}
+
+// nextOption returns the option following current, wrapping around. Unknown
+// values move to the first option.
+func nextOption[T comparable](options []T, current T) T {
+	index := slices.Index(options, current)
+	return options[(index+1)%len(options)]
+}
+
+func createSettingsKeyboard(lang string, settings model.Settings) tgbotapi.InlineKeyboardMarkup {
+	language := tr(lang, "language.auto")
+	for _, locale := range i18n.Locales {
+		if locale.Code == settings.Language {
+			language = locale.Name
+		}
+	}
+
+	button := func(label string, field string) []tgbotapi.InlineKeyboardButton {
+		return tgbotapi.NewInlineKeyboardRow(tgbotapi.NewInlineKeyboardButtonData(label, "settings:"+field))
+	}
+	return tgbotapi.NewInlineKeyboardMarkup(
+		button(tr(lang, "settings.page", settings.ResultsPerPage), "page"),
+		button(tr(lang, "settings.layout", tr(lang, "layout."+settings.Layout)), "layout"),
+		button(tr(lang, "settings.language", language), "language"),
+		button(tr(lang, "settings.titles", tr(lang, "titles."+settings.TitleMode)), "titles"),
+		button(checkbox(settings.HideAdult)+tr(lang, "settings.hide_adult"), "adult"),
+		button(checkbox(settings.NotifyAnnouncements)+tr(lang, "settings.announcements"), "announcements"),
+		button(checkbox(settings.NotifyMovieNights)+tr(lang, "settings.movie_nights"), "movienights"),
+	)
+}
+
+func checkbox(checked bool) string {
+	if checked {
+		return "✅ "
+	}
+	return "❌ "
+}
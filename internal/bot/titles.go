@@ -0,0 +1,73 @@
+package bot
+
+import (
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"kinopoisk-bot/internal/model"
+	"log/slog"
+)
+
+// Title display modes. Their labels are the "titles.<mode>" messages.
+const (
+	titleModeRussian  = "russian"
+	titleModeOriginal = "original"
+	titleModeBoth     = "both"
+)
+
+var titleModes = []string{titleModeRussian, titleModeOriginal, titleModeBoth}
+
+// displayTitle returns the movie title as the user wants to see it.
+func displayTitle(mode string, movie model.Movie) string {
+	if movie.OriginalTitle == "" || movie.OriginalTitle == movie.Title {
+		return movie.Title
+	}
+	switch mode {
+	case titleModeOriginal:
+		return movie.OriginalTitle
+	case titleModeBoth:
+		return movie.Title + " / " + movie.OriginalTitle
+	default:
+		return movie.Title
+	}
+}
+
+// applyTitleMode retitles movies according to the user's preference before
+// they are shown.
+func applyTitleMode(mode string, movies []model.Movie) {
+	for i := range movies {
+		movies[i].Title = displayTitle(mode, movies[i])
+	}
+}
+
+func (b *Bot) handleTitlesCommand(chatID int64, lang string, userID int64) {
+	current := b.loadSettings(userID).TitleMode
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, mode := range titleModes {
+		label := tr(lang, "titles."+mode)
+		if mode == current {
+			label = "✅ " + label
+		}
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(label, "titles:"+mode),
+		))
+	}
+
+	msg := tgbotapi.NewMessage(chatID, tr(lang, "titles.choose"))
+	msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	if _, err := b.sender.Send(msg); err != nil {
+		slog.Error("Error sending title mode menu", "error", err)
+	}
+}
+
+func (b *Bot) handleTitlesSelect(query *tgbotapi.CallbackQuery, lang string, mode string) {
+	if mode != titleModeRussian && mode != titleModeOriginal && mode != titleModeBoth {
+		slog.Warn("Unknown title mode", "mode", mode)
+		return
+	}
+	settings := b.loadSettings(query.From.ID)
+	settings.TitleMode = mode
+	if err := b.redis.SaveSettings(query.From.ID, settings); err != nil {
+		slog.Error("Error saving title mode to Redis", "error", err)
+		return
+	}
+	b.sendText(query.Message.Chat.ID, tr(lang, "titles.changed"))
+}
@@ -0,0 +1,95 @@
+package bot
+
+import (
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"html"
+	"kinopoisk-bot/internal/model"
+	"log/slog"
+	"sort"
+	"strings"
+	"unicode"
+)
+
+const maxTrailerButtons = 5
+
+func (b *Bot) handleTrailer(chatID int64, settings model.Settings, movieID int) {
+	lang := settings.Language
+	movie, err := b.kinopoisk.GetMovieByID(movieID)
+	if err != nil {
+		b.sendText(chatID, tr(lang, "movie.load_failed"))
+		return
+	}
+	movie.Title = displayTitle(settings.TitleMode, *movie)
+	if len(movie.Trailers) == 0 {
+		b.sendText(chatID, tr(lang, "trailer.none", movie.Title))
+		return
+	}
+
+	trailers := rankTrailers(movie.Trailers)
+	best := trailers[0]
+
+	text := tr(lang, "trailer.title", html.EscapeString(movie.Title)) +
+		fmt.Sprintf("\n<a href=\"%s\">%s</a>", html.EscapeString(best.URL), html.EscapeString(trailerTitle(lang, best)))
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ParseMode = "HTML"
+
+	if len(trailers) > 1 {
+		var rows [][]tgbotapi.InlineKeyboardButton
+		for i, trailer := range trailers[1:] {
+			if i == maxTrailerButtons {
+				break
+			}
+			rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+				tgbotapi.NewInlineKeyboardButtonURL(truncateRunes(trailerTitle(lang, trailer), 60), trailer.URL),
+			))
+		}
+		msg.ReplyMarkup = tgbotapi.NewInlineKeyboardMarkup(rows...)
+	}
+
+	if _, err := b.sender.Send(msg); err != nil {
+		slog.Error("Error sending trailer", "error", err)
+	}
+}
+
+// rankTrailers orders trailers so that Russian-language ones come first and,
+// within each language, videos from YouTube and Kinopoisk come before others.
+func rankTrailers(trailers []model.Trailer) []model.Trailer {
+	ranked := make([]model.Trailer, len(trailers))
+	copy(ranked, trailers)
+	sort.SliceStable(ranked, func(i, j int) bool {
+		return trailerScore(ranked[i]) > trailerScore(ranked[j])
+	})
+	return ranked
+}
+
+func trailerScore(trailer model.Trailer) int {
+	score := 0
+	if isRussianTrailer(trailer) {
+		score += 2
+	}
+	if strings.Contains(trailer.Site, "youtube") || strings.Contains(trailer.Site, "kinopoisk") {
+		score++
+	}
+	return score
+}
+
+func isRussianTrailer(trailer model.Trailer) bool {
+	name := strings.ToLower(trailer.Name)
+	if strings.Contains(name, "рус") || strings.Contains(name, "дубл") {
+		return true
+	}
+	for _, r := range name {
+		if unicode.Is(unicode.Cyrillic, r) {
+			return true
+		}
+	}
+	return false
+}
+
+func trailerTitle(lang string, trailer model.Trailer) string {
+	if trailer.Name != "" {
+		return trailer.Name
+	}
+	return tr(lang, "trailer.default_name")
+}
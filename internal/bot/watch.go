@@ -0,0 +1,144 @@
+package bot
+
+import (
+	"fmt"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"kinopoisk-bot/internal/model"
+	"log/slog"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// watchServices are the streaming services a user can mark as subscribed.
+// Platform names in the API vary in spelling, so each service is matched by
+// keywords.
+var watchServices = []struct {
+	name     string
+	keywords []string
+}{
+	{"Кинопоиск", []string{"кинопоиск", "kinopoisk"}},
+	{"Okko", []string{"okko"}},
+	{"Иви", []string{"иви", "ivi"}},
+	{"Wink", []string{"wink"}},
+	{"START", []string{"start"}},
+	{"PREMIER", []string{"premier"}},
+	{"Амедиатека", []string{"амедиатека", "amediateka"}},
+	{"KION", []string{"kion"}},
+	{"more.tv", []string{"more.tv", "more tv"}},
+}
+
+// handleWatch lists legal streaming services for a movie. Unless showAll is
+// set, options are limited to the user's subscriptions when they have any.
+func (b *Bot) handleWatch(chatID int64, settings model.Settings, userID int64, messageID int, movieID int, showAll bool) {
+	lang := settings.Language
+	movie, err := b.kinopoisk.GetMovieByID(movieID)
+	if err != nil {
+		b.sendText(chatID, tr(lang, "movie.load_failed"))
+		return
+	}
+	movie.Title = displayTitle(settings.TitleMode, *movie)
+
+	subscriptions, err := b.redis.GetWatchServices(userID)
+	if err != nil {
+		slog.Error("Error getting watch services from Redis", "error", err)
+	}
+
+	options := movie.WatchOptions
+	filtered := !showAll && len(subscriptions) > 0
+	if filtered {
+		options = filterWatchOptions(options, subscriptions)
+	}
+
+	var text string
+	switch {
+	case len(movie.WatchOptions) == 0:
+		text = tr(lang, "watch.unavailable", movie.Title)
+	case len(options) == 0:
+		text = tr(lang, "watch.not_in_services", movie.Title)
+	default:
+		text = tr(lang, "watch.title", movie.Title)
+	}
+
+	var rows [][]tgbotapi.InlineKeyboardButton
+	for _, option := range options {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonURL(option.Name, option.URL),
+		))
+	}
+	id := strconv.Itoa(movieID)
+	if filtered && len(options) < len(movie.WatchOptions) {
+		rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(tr(lang, "watch.show_all"), "watch:"+id+":all"),
+		))
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr(lang, "watch.my_services"), "watch_services:"+id),
+	))
+
+	b.sendOrEdit(chatID, messageID, text, tgbotapi.NewInlineKeyboardMarkup(rows...))
+}
+
+func (b *Bot) handleWatchServicesMenu(chatID int64, lang string, userID int64, messageID int, movieID int) {
+	subscriptions, err := b.redis.GetWatchServices(userID)
+	if err != nil {
+		slog.Error("Error getting watch services from Redis", "error", err)
+	}
+
+	id := strconv.Itoa(movieID)
+	var rows [][]tgbotapi.InlineKeyboardButton
+	var row []tgbotapi.InlineKeyboardButton
+	for i, service := range watchServices {
+		label := service.name
+		if slices.Contains(subscriptions, service.name) {
+			label = "✅ " + label
+		}
+		row = append(row, tgbotapi.NewInlineKeyboardButtonData(label,
+			fmt.Sprintf("watch_toggle:%s:%d", id, i)))
+		if len(row) == 2 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+	rows = append(rows, tgbotapi.NewInlineKeyboardRow(
+		tgbotapi.NewInlineKeyboardButtonData(tr(lang, "watch.back"), "watch:"+id),
+	))
+
+	b.sendOrEdit(chatID, messageID, tr(lang, "watch.services_prompt"), tgbotapi.NewInlineKeyboardMarkup(rows...))
+}
+
+func (b *Bot) handleWatchServiceToggle(chatID int64, lang string, userID int64, messageID int, movieID int, index int) {
+	if index < 0 || index >= len(watchServices) {
+		slog.Warn("Unknown watch service", "index", index)
+		return
+	}
+	if err := b.redis.ToggleWatchService(userID, watchServices[index].name); err != nil {
+		slog.Error("Error toggling watch service in Redis", "error", err)
+		return
+	}
+	b.handleWatchServicesMenu(chatID, lang, userID, messageID, movieID)
+}
+
+func filterWatchOptions(options []model.WatchOption, subscriptions []string) []model.WatchOption {
+	var keywords []string
+	for _, service := range watchServices {
+		if slices.Contains(subscriptions, service.name) {
+			keywords = append(keywords, service.keywords...)
+		}
+	}
+
+	var filtered []model.WatchOption
+	for _, option := range options {
+		name := strings.ToLower(option.Name)
+		for _, keyword := range keywords {
+			if strings.Contains(name, keyword) {
+				filtered = append(filtered, option)
+				break
+			}
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,251 @@
+package i18n
+
+var en = map[string]string{
+	"start.greeting": "Hi! I search Kinopoisk for movies, actors and directors.",
+	"start.choose":   "Choose what to search for:",
+	"help": "How to use the bot:\n\n" +
+		"1. Choose what to search for\n" +
+		"2. Enter a search query\n\n" +
+		"Commands:\n" +
+		"/start - get started\n" +
+		"/search [query] - search movies\n" +
+		"/person [query] - search actors and directors\n" +
+		"/random - a random movie matching your filters\n" +
+		"/movienight - host a movie night in a group\n" +
+		"/language - choose the interface language\n" +
+		"/titles - Russian or original movie titles\n" +
+		"/settings - settings\n" +
+		"/help - show this help\n\n" +
+		"In groups the bot answers commands, mentions and replies to its messages.",
+
+	"language.choose":  "Choose the interface language:",
+	"language.auto":    "🌐 Same as Telegram",
+	"language.changed": "Interface language changed",
+
+	"titles.choose":   "How should movie titles be shown?",
+	"titles.russian":  "🇷🇺 Russian",
+	"titles.original": "🌍 Original",
+	"titles.both":     "🔀 Both",
+	"titles.changed":  "Title preference saved",
+
+	"layout.media":   "posters",
+	"layout.compact": "compact",
+	"layout.collage": "collage",
+
+	"settings.title":         "⚙ Settings. Tap an item to change it.",
+	"settings.page":          "📄 Results per page: %d",
+	"settings.layout":        "🖼 Results layout: %s",
+	"settings.language":      "🌐 Language: %s",
+	"settings.titles":        "🎬 Titles: %s",
+	"settings.hide_adult":    "Hide 18+ movies",
+	"settings.announcements": "Bot news",
+	"settings.movie_nights":  "Movie night results in private",
+
+	"menu.movie_search":  "🎬 Search movies",
+	"menu.person_search": "👤 Search actors/directors",
+	"menu.random":        "🎲 What to watch?",
+
+	"search.prompt.movie":  "Enter a movie title to search for:",
+	"search.prompt.person": "Enter the name of an actor or director to search for:",
+	"search.choose_type":   "Please choose what to search for with the buttons below 👇",
+	"search.empty_query":   "Please enter a search query",
+	"search.cancel":        "❌ Cancel search",
+	"search.cancelled":     "Search cancelled",
+
+	"session.expired":   "The search session has expired. Please start a new search.",
+	"session.not_yours": "This is not your search. Start your own with /search",
+
+	"movies.not_found":         "No movies found",
+	"movies.no_more":           "No more movies found",
+	"movies.preparing_posters": "⏳ Preparing posters..",
+	"movie.load_failed":        "Failed to load the movie",
+	"movie.hidden":             "This movie is hidden by the adult content filter. You can turn it off in /settings",
+	"movies.all_hidden":        "All movies on this page are hidden by the adult content filter",
+
+	"persons.not_found":  "No actors or directors found",
+	"persons.no_more":    "No more actors or directors found",
+	"persons.results":    "Actors and directors found:",
+	"person.load_failed": "Failed to load the person",
+	"person.death_age":   "✝ %s (aged %d)",
+	"person.age":         "🎂 Age: %d",
+	"person.height":      "📏 Height: %d cm",
+	"person.facts":       "Facts:",
+	"person.all_movies":  "🎞 All movies",
+
+	"pagination.page":  "Page: %d",
+	"pagination.short": "p. %d/%d",
+
+	"card.seasons":   "📺 Seasons",
+	"card.trailer":   "🎞 Trailer",
+	"card.watch":     "📍 Where to watch",
+	"card.reviews":   "💬 Reviews",
+	"card.similar":   "Similar",
+	"card.sequels":   "Sequels/prequels",
+	"card.kinopoisk": "Kinopoisk",
+
+	"role.all":      "🎞 All",
+	"role.actor":    "🎭 Actor",
+	"role.director": "🎬 Director",
+	"role.writer":   "✍ Writer",
+	"role.producer": "💼 Producer",
+
+	"sort.popularity": "🔥 Popular",
+	"sort.year":       "📅 Newest",
+	"sort.rating":     "⭐ Rating",
+
+	"rating.kp":              "KP",
+	"rating.imdb":            "IMDb",
+	"rating.critics":         "Critics",
+	"rating.russian_critics": "Russian critics %.0f%%",
+	"rating.await":           "Awaiting %.0f%%",
+
+	"series":                        "📺 Series",
+	"series.length":                 "%d min. episodes",
+	"series.status.announced":       "announced",
+	"series.status.pre-production":  "in pre-production",
+	"series.status.filming":         "filming",
+	"series.status.post-production": "in post-production",
+	"series.status.completed":       "completed",
+
+	"seasons.load_failed": "Failed to load seasons",
+	"seasons.empty":       "No season information yet",
+	"seasons.not_found":   "Season not found",
+	"seasons.button":      "Season %d (%d ep.)",
+	"seasons.choose":      "📺 Choose a season:",
+	"seasons.back":        "📺 Back to seasons",
+	"season.title":        "Season %d",
+	"season.no_episodes":  "The episode list is not available yet",
+
+	"trailer.none":         "«%s» has no trailers 😔",
+	"trailer.title":        "🎞 «%s» trailer:",
+	"trailer.default_name": "Trailer",
+
+	"reviews.load_failed": "Failed to load reviews",
+	"reviews.empty":       "No reviews yet",
+	"reviews.title":       "<b>💬 Audience reviews</b> (p. %d/%d)",
+	"review.load_failed":  "Failed to load the review",
+
+	"watch.unavailable":     "😔 «%s» can't be watched legally online yet",
+	"watch.not_in_services": "😔 «%s» is not on your services",
+	"watch.title":           "📍 Where to watch «%s»:",
+	"watch.show_all":        "Show all services",
+	"watch.my_services":     "⚙ My services",
+	"watch.back":            "⬅ Where to watch",
+	"watch.services_prompt": "⚙ Mark the services you are subscribed to. " +
+		"Only they will be shown under «Where to watch».",
+
+	"random.not_found":            "Couldn't find a movie matching your filters 😔",
+	"random.filter":               "⚙ Filters",
+	"random.filter_current":       "⚙ Filters: %s",
+	"random.filter_title":         "🎲 Random pick filters: %s",
+	"random.retry":                "🎲 Try again",
+	"random.more":                 "🎲 Another",
+	"random.pick":                 "🎲 Pick a movie",
+	"random.watchlist_add":        "📌 Watch later",
+	"random.watchlist_added":      "📌 Added to «Watch later»",
+	"random.any_genre":            "any genre",
+	"random.any_rating":           "any",
+	"random.any_years":            "any years",
+	"random.rating_from":          "⭐ %g+",
+	"random.keep_watchlist":       "❌ Include my list",
+	"random.exclude_watchlist":    "✅ Exclude my list",
+	"random.summary_rating":       "%g⭐+",
+	"random.summary_since":        "since %d",
+	"random.summary_no_watchlist": "excluding my list",
+	"random.summary_any":          "no filters",
+
+	"genre.drama":       "drama",
+	"genre.comedy":      "comedy",
+	"genre.action":      "action",
+	"genre.thriller":    "thriller",
+	"genre.horror":      "horror",
+	"genre.scifi":       "sci-fi",
+	"genre.romance":     "romance",
+	"genre.detective":   "detective",
+	"genre.animation":   "animation",
+	"genre.adventure":   "adventure",
+	"genre.fantasy":     "fantasy",
+	"genre.crime":       "crime",
+	"genre.documentary": "documentary",
+	"genre.anime":       "anime",
+
+	"decade.before1980": "before 1980",
+	"decade.1980s":      "80s",
+	"decade.1990s":      "90s",
+	"decade.2000s":      "2000s",
+	"decade.2010s":      "2010s",
+	"decade.2020s":      "2020s",
+
+	"movienight.group_only": "Movie nights can only be hosted in groups",
+	"movienight.usage": "Movie night:\n" +
+		"/movienight - start collecting nominations or show the current list\n" +
+		"/movienight vote [duration] - close nominations and start the vote (e.g. 2h or 30m)\n" +
+		"/movienight finish - count the votes before the deadline\n" +
+		"/movienight cancel - cancel the movie night",
+	"movienight.started": "🍿 Movie night has started! Find movies with /search and " +
+		"nominate them with the 🗳 buttons under the results.",
+	"movienight.voting":                "🗳 Voting is in progress, results at %s UTC",
+	"movienight.nominating":            "🍿 Collecting nominations",
+	"movienight.no_nominees":           "Nothing has been nominated yet.",
+	"movienight.nominees":              "Nominees:",
+	"movienight.not_started":           "No movie night in progress",
+	"movienight.not_started_hint":      "No movie night in progress. Start one with /movienight",
+	"movienight.vote_organizer_only":   "Only the organizer can start the vote",
+	"movienight.cancel_organizer_only": "Only the organizer can cancel the movie night",
+	"movienight.too_few_nominees":      "The vote needs at least two nominees",
+	"movienight.bad_duration":          "Couldn't parse the duration. Example: /movienight vote 2h",
+	"movienight.poll_question":         "🍿 What are we watching tonight?",
+	"movienight.poll_failed":           "Failed to create the poll",
+	"movienight.cancelled":             "Movie night cancelled",
+	"movienight.nominations_closed":    "Nominations are closed",
+	"movienight.already_nominated":     "This movie is already nominated",
+	"movienight.too_many_nominees":     "You can nominate at most %d movies",
+	"movienight.nominated":             "Nominated: %s",
+	"movienight.adult_hidden":          "The organizer hides adult movies, so this one can't be nominated",
+	"movienight.nominee_added":         "🗳 %s nominates «%s» (%d/%d)",
+	"movienight.poll_unavailable":      "Couldn't count the movie night votes: the poll is unavailable",
+	"movienight.no_votes":              "Voting is over, but nobody voted 😔",
+	"movienight.result_notice":         "🏆 Movie night is over, «%s» won",
+	"movienight.winner":                "🏆 Movie night results: «%s» wins (%d votes)",
+	"movienight.tie":                   "🏆 Movie night results: %s are tied with %d votes each, the earliest nominated «%s» wins",
+	"movienight.finish_organizer_only": "Only the organizer can finish the vote",
+	"movienight.not_voting":            "Voting hasn't started yet. Start it with /movienight vote",
+
+	"maintenance.notice": "🛠 The bot is under maintenance. Please try again later.",
+
+	"admin.stats.title":          "📊 Statistics",
+	"admin.stats.users":          "👥 Users: %d (%d inactive)",
+	"admin.stats.banned":         "🚫 Banned: %d",
+	"admin.stats.searches":       "🔍 Searches: %d",
+	"admin.stats.image_cache":    "🖼 Posters in memory: %d (%s)",
+	"admin.stats.disk_cache":     "💾 Posters on disk: %d (%s)",
+	"admin.stats.response_cache": "📦 Cached API responses: %d",
+	"admin.stats.api_quota":      "🔑 API requests today: %d of %d",
+	"admin.stats.api_requests":   "🔑 API requests today: %d",
+	"admin.cache_cleared":        "🧹 Caches cleared: %d posters in memory, %d on disk, %d API responses",
+	"admin.ban.usage":            "Give a user ID or reply to their message: /ban 123456",
+	"admin.ban.admin":            "Admins can't be banned",
+	"admin.banned":               "🚫 User %d is banned",
+	"admin.unbanned":             "✅ User %d is unbanned",
+	"admin.maintenance.on":       "🛠 Maintenance mode is on",
+	"admin.maintenance.off":      "✅ Maintenance mode is off",
+	"admin.maintenance.usage":    "Usage: /maintenance on|off",
+	"admin.reloaded":             "🔄 Configuration reloaded",
+	"admin.reload_failed":        "Failed to reload the configuration: %v",
+
+	"broadcast.usage":     "Usage: /broadcast announcement text",
+	"broadcast.preview":   "This is how users will see the announcement:",
+	"broadcast.send":      "✅ Send to everyone",
+	"broadcast.cancel":    "❌ Cancel",
+	"broadcast.expired":   "The broadcast draft was not found or has already been sent",
+	"broadcast.started":   "📣 Broadcast started, a report will follow when it's done",
+	"broadcast.cancelled": "Broadcast cancelled",
+	"broadcast.finished": "📣 Broadcast finished\n" +
+		"Delivered: %d\nBlocked the bot: %d\nNever started the bot: %d\nBanned: %d\n" +
+		"Opted out of news: %d\nErrors: %d",
+
+	"rate_limit.too_often": "⏳ Too often! Wait a moment and try again.",
+	"rate_limit.repeated":  "⏳ Already working on it, please wait",
+	"rate_limit.search":    "⏳ Too often! You can search again in a minute.",
+	"rate_limit.busy":      "⏳ The bot can't keep up with this chat, try again a bit later",
+}
@@ -0,0 +1,65 @@
+package i18n
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+const (
+	Russian = "ru"
+	English = "en"
+
+	DefaultLocale = Russian
+)
+
+// Locales lists the supported UI languages with their names in that language.
+var Locales = []struct {
+	Code string
+	Name string
+}{
+	{Russian, "🇷🇺 Русский"},
+	{English, "🇬🇧 English"},
+}
+
+var catalogs = map[string]map[string]string{
+	Russian: ru,
+	English: en,
+}
+
+// T returns the message for key in the given locale, formatted with args.
+// Messages missing from the locale's catalog fall back to the default locale.
+func T(locale string, key string, args ...any) string {
+	message, ok := catalogs[locale][key]
+	if !ok {
+		message, ok = catalogs[DefaultLocale][key]
+	}
+	if !ok {
+		slog.Warn("Missing translation", "locale", locale, "key", key)
+		return key
+	}
+	if len(args) == 0 {
+		return message
+	}
+	return fmt.Sprintf(message, args...)
+}
+
+// Resolve maps a Telegram language_code to a supported locale. Users of
+// languages close to Russian get the Russian UI, everyone else gets English.
+func Resolve(languageCode string) string {
+	if languageCode == "" {
+		return DefaultLocale
+	}
+	base, _, _ := strings.Cut(strings.ToLower(languageCode), "-")
+	switch base {
+	case Russian, "uk", "be", "kk":
+		return Russian
+	default:
+		return English
+	}
+}
+
+func IsSupported(locale string) bool {
+	_, ok := catalogs[locale]
+	return ok
+}
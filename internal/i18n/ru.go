@@ -0,0 +1,251 @@
+package i18n
+
+var ru = map[string]string{
+	"start.greeting": "Привет! Я бот для поиска фильмов и актеров/режиссеров в Кинопоиске.",
+	"start.choose":   "Выберите тип поиска:",
+	"help": "Как использовать бота:\n\n" +
+		"1. Выберите тип поиска\n" +
+		"2. Введите запрос для поиска\n\n" +
+		"Доступные команды:\n" +
+		"/start - начать работу\n" +
+		"/search [запрос] - поиск фильмов\n" +
+		"/person [запрос] - поиск актеров/режиссеров\n" +
+		"/random - случайный фильм с учетом ваших условий\n" +
+		"/movienight - устроить киновечер в группе\n" +
+		"/language - выбрать язык интерфейса\n" +
+		"/titles - русские или оригинальные названия фильмов\n" +
+		"/settings - настройки\n" +
+		"/help - показать справку\n\n" +
+		"В группах бот отвечает на команды, упоминания и ответы на свои сообщения.",
+
+	"language.choose":  "Выберите язык интерфейса:",
+	"language.auto":    "🌐 Как в Telegram",
+	"language.changed": "Язык интерфейса изменен",
+
+	"titles.choose":   "Как показывать названия фильмов?",
+	"titles.russian":  "🇷🇺 Русское",
+	"titles.original": "🌍 Оригинальное",
+	"titles.both":     "🔀 Оба",
+	"titles.changed":  "Настройка названий сохранена",
+
+	"layout.media":   "постеры",
+	"layout.compact": "компактно",
+	"layout.collage": "коллаж",
+
+	"settings.title":         "⚙ Настройки. Нажмите на пункт, чтобы изменить его.",
+	"settings.page":          "📄 Результатов на странице: %d",
+	"settings.layout":        "🖼 Вид результатов: %s",
+	"settings.language":      "🌐 Язык: %s",
+	"settings.titles":        "🎬 Названия: %s",
+	"settings.hide_adult":    "Скрывать фильмы 18+",
+	"settings.announcements": "Новости бота",
+	"settings.movie_nights":  "Итоги киновечеров в личку",
+
+	"menu.movie_search":  "🎬 Поиск фильмов",
+	"menu.person_search": "👤 Поиск актеров/режиссеров",
+	"menu.random":        "🎲 Что посмотреть?",
+
+	"search.prompt.movie":  "Введите название фильма для поиска:",
+	"search.prompt.person": "Введите имя актера или режиссера для поиска:",
+	"search.choose_type":   "Пожалуйста, выберите тип поиска с помощью кнопок ниже 👇",
+	"search.empty_query":   "Пожалуйста, укажите запрос для поиска",
+	"search.cancel":        "❌ Отменить поиск",
+	"search.cancelled":     "Поиск отменен",
+
+	"session.expired":   "Сессия поиска истекла. Пожалуйста, начните поиск заново.",
+	"session.not_yours": "Это не ваш поиск. Начните свой с помощью /search",
+
+	"movies.not_found":         "Фильмы не найдены",
+	"movies.no_more":           "Больше фильмов не найдено",
+	"movies.preparing_posters": "⏳ Подготавливаю постеры..",
+	"movie.load_failed":        "Не удалось загрузить информацию о фильме",
+	"movie.hidden":             "Фильм скрыт фильтром для взрослых. Его можно отключить в /settings",
+	"movies.all_hidden":        "Все фильмы на этой странице скрыты фильтром для взрослых",
+
+	"persons.not_found":  "Актеры/режиссеры не найдены",
+	"persons.no_more":    "Больше актеров/режиссеров не найдено",
+	"persons.results":    "Результаты поиска актеров/режиссеров:",
+	"person.load_failed": "Не удалось загрузить информацию о персоне",
+	"person.death_age":   "✝ %s (в возрасте %d)",
+	"person.age":         "🎂 Возраст: %d",
+	"person.height":      "📏 Рост: %d см",
+	"person.facts":       "Факты:",
+	"person.all_movies":  "🎞 Все фильмы",
+
+	"pagination.page":  "Страница: %d",
+	"pagination.short": "стр. %d/%d",
+
+	"card.seasons":   "📺 Сезоны",
+	"card.trailer":   "🎞 Трейлер",
+	"card.watch":     "📍 Где посмотреть",
+	"card.reviews":   "💬 Отзывы",
+	"card.similar":   "Похожие",
+	"card.sequels":   "Сиквелы/приквелы",
+	"card.kinopoisk": "Кинопоиск",
+
+	"role.all":      "🎞 Все",
+	"role.actor":    "🎭 Актер",
+	"role.director": "🎬 Режиссер",
+	"role.writer":   "✍ Сценарист",
+	"role.producer": "💼 Продюсер",
+
+	"sort.popularity": "🔥 Популярные",
+	"sort.year":       "📅 Новые",
+	"sort.rating":     "⭐ Рейтинг",
+
+	"rating.kp":              "КП",
+	"rating.imdb":            "IMDb",
+	"rating.critics":         "Критики",
+	"rating.russian_critics": "Рос. критики %.0f%%",
+	"rating.await":           "Ждут %.0f%%",
+
+	"series":                        "📺 Сериал",
+	"series.length":                 "серия %d мин.",
+	"series.status.announced":       "анонсирован",
+	"series.status.pre-production":  "в подготовке",
+	"series.status.filming":         "идут съемки",
+	"series.status.post-production": "в постпродакшене",
+	"series.status.completed":       "завершен",
+
+	"seasons.load_failed": "Не удалось загрузить сезоны",
+	"seasons.empty":       "Информации о сезонах пока нет",
+	"seasons.not_found":   "Сезон не найден",
+	"seasons.button":      "Сезон %d (%d эп.)",
+	"seasons.choose":      "📺 Выберите сезон:",
+	"seasons.back":        "📺 К сезонам",
+	"season.title":        "Сезон %d",
+	"season.no_episodes":  "Список серий пока недоступен",
+
+	"trailer.none":         "У «%s» нет трейлеров 😔",
+	"trailer.title":        "🎞 Трейлер «%s»:",
+	"trailer.default_name": "Трейлер",
+
+	"reviews.load_failed": "Не удалось загрузить отзывы",
+	"reviews.empty":       "Отзывов пока нет",
+	"reviews.title":       "<b>💬 Отзывы зрителей</b> (стр. %d/%d)",
+	"review.load_failed":  "Не удалось загрузить отзыв",
+
+	"watch.unavailable":     "😔 «%s» пока нельзя посмотреть легально онлайн",
+	"watch.not_in_services": "😔 «%s» нет в ваших сервисах",
+	"watch.title":           "📍 Где посмотреть «%s»:",
+	"watch.show_all":        "Показать все сервисы",
+	"watch.my_services":     "⚙ Мои сервисы",
+	"watch.back":            "⬅ Где посмотреть",
+	"watch.services_prompt": "⚙ Отметьте сервисы, на которые вы подписаны. " +
+		"В списке «Где посмотреть» будут показаны только они.",
+
+	"random.not_found":            "Не удалось подобрать фильм по заданным условиям 😔",
+	"random.filter":               "⚙ Условия",
+	"random.filter_current":       "⚙ Условия: %s",
+	"random.filter_title":         "🎲 Условия случайного выбора: %s",
+	"random.retry":                "🎲 Ещё раз",
+	"random.more":                 "🎲 Ещё",
+	"random.pick":                 "🎲 Выбрать фильм",
+	"random.watchlist_add":        "📌 Буду смотреть",
+	"random.watchlist_added":      "📌 Добавлено в список «Буду смотреть»",
+	"random.any_genre":            "любой жанр",
+	"random.any_rating":           "любой",
+	"random.any_years":            "любые годы",
+	"random.rating_from":          "⭐ от %g",
+	"random.keep_watchlist":       "❌ Не исключать мой список",
+	"random.exclude_watchlist":    "✅ Исключать мой список",
+	"random.summary_rating":       "от %g⭐",
+	"random.summary_since":        "с %d",
+	"random.summary_no_watchlist": "без моего списка",
+	"random.summary_any":          "без ограничений",
+
+	"genre.drama":       "драма",
+	"genre.comedy":      "комедия",
+	"genre.action":      "боевик",
+	"genre.thriller":    "триллер",
+	"genre.horror":      "ужасы",
+	"genre.scifi":       "фантастика",
+	"genre.romance":     "мелодрама",
+	"genre.detective":   "детектив",
+	"genre.animation":   "мультфильм",
+	"genre.adventure":   "приключения",
+	"genre.fantasy":     "фэнтези",
+	"genre.crime":       "криминал",
+	"genre.documentary": "документальный",
+	"genre.anime":       "аниме",
+
+	"decade.before1980": "до 1980",
+	"decade.1980s":      "80-е",
+	"decade.1990s":      "90-е",
+	"decade.2000s":      "2000-е",
+	"decade.2010s":      "2010-е",
+	"decade.2020s":      "2020-е",
+
+	"movienight.group_only": "Киновечер можно устроить только в группе",
+	"movienight.usage": "Киновечер:\n" +
+		"/movienight - начать сбор номинаций или показать текущий список\n" +
+		"/movienight vote [длительность] - закрыть номинации и начать голосование (например, 2h или 30m)\n" +
+		"/movienight finish - подвести итоги голосования досрочно\n" +
+		"/movienight cancel - отменить киновечер",
+	"movienight.started": "🍿 Киновечер начался! Ищите фильмы через /search и " +
+		"номинируйте их кнопками 🗳 под результатами.",
+	"movienight.voting":                "🗳 Идет голосование, итоги в %s UTC",
+	"movienight.nominating":            "🍿 Идет сбор номинаций",
+	"movienight.no_nominees":           "Пока никто ничего не номинировал.",
+	"movienight.nominees":              "Номинанты:",
+	"movienight.not_started":           "Киновечер не начат",
+	"movienight.not_started_hint":      "Киновечер не начат. Начните его командой /movienight",
+	"movienight.vote_organizer_only":   "Начать голосование может только организатор киновечера",
+	"movienight.cancel_organizer_only": "Отменить киновечер может только организатор",
+	"movienight.too_few_nominees":      "Для голосования нужно хотя бы два номинанта",
+	"movienight.bad_duration":          "Не удалось разобрать длительность. Пример: /movienight vote 2h",
+	"movienight.poll_question":         "🍿 Что смотрим на киновечере?",
+	"movienight.poll_failed":           "Не удалось создать голосование",
+	"movienight.cancelled":             "Киновечер отменен",
+	"movienight.nominations_closed":    "Сбор номинаций закрыт",
+	"movienight.already_nominated":     "Этот фильм уже номинирован",
+	"movienight.too_many_nominees":     "Можно номинировать не больше %d фильмов",
+	"movienight.nominated":             "Номинировано: %s",
+	"movienight.adult_hidden":          "Организатор скрывает фильмы для взрослых, этот фильм номинировать нельзя",
+	"movienight.nominee_added":         "🗳 %s номинирует «%s» (%d/%d)",
+	"movienight.poll_unavailable":      "Не удалось подвести итоги киновечера: голосование недоступно",
+	"movienight.no_votes":              "Голосование завершено, но никто не проголосовал 😔",
+	"movienight.result_notice":         "🏆 Киновечер завершен, победил «%s»",
+	"movienight.winner":                "🏆 Итоги киновечера: побеждает «%s» (%d голосов)",
+	"movienight.tie":                   "🏆 Итоги киновечера: ничья между %s (по %d голосов), побеждает номинированный раньше «%s»",
+	"movienight.finish_organizer_only": "Подвести итоги может только организатор киновечера",
+	"movienight.not_voting":            "Голосование еще не началось. Начните его командой /movienight vote",
+
+	"maintenance.notice": "🛠 Бот на техническом обслуживании. Попробуйте позже.",
+
+	"admin.stats.title":          "📊 Статистика",
+	"admin.stats.users":          "👥 Пользователей: %d (неактивных %d)",
+	"admin.stats.banned":         "🚫 Заблокировано: %d",
+	"admin.stats.searches":       "🔍 Поисков: %d",
+	"admin.stats.image_cache":    "🖼 Постеров в памяти: %d (%s)",
+	"admin.stats.disk_cache":     "💾 Постеров на диске: %d (%s)",
+	"admin.stats.response_cache": "📦 Ответов API в кэше: %d",
+	"admin.stats.api_quota":      "🔑 Запросов к API сегодня: %d из %d",
+	"admin.stats.api_requests":   "🔑 Запросов к API сегодня: %d",
+	"admin.cache_cleared":        "🧹 Кэши очищены: постеров в памяти %d, на диске %d, ответов API %d",
+	"admin.ban.usage":            "Укажите ID пользователя или ответьте на его сообщение: /ban 123456",
+	"admin.ban.admin":            "Администратора нельзя заблокировать",
+	"admin.banned":               "🚫 Пользователь %d заблокирован",
+	"admin.unbanned":             "✅ Пользователь %d разблокирован",
+	"admin.maintenance.on":       "🛠 Режим обслуживания включен",
+	"admin.maintenance.off":      "✅ Режим обслуживания выключен",
+	"admin.maintenance.usage":    "Использование: /maintenance on|off",
+	"admin.reloaded":             "🔄 Конфигурация перезагружена",
+	"admin.reload_failed":        "Не удалось перезагрузить конфигурацию: %v",
+
+	"broadcast.usage":     "Использование: /broadcast текст объявления",
+	"broadcast.preview":   "Так объявление увидят пользователи:",
+	"broadcast.send":      "✅ Отправить всем",
+	"broadcast.cancel":    "❌ Отмена",
+	"broadcast.expired":   "Черновик рассылки не найден или уже отправлен",
+	"broadcast.started":   "📣 Рассылка запущена, отчет придет по завершении",
+	"broadcast.cancelled": "Рассылка отменена",
+	"broadcast.finished": "📣 Рассылка завершена\n" +
+		"Доставлено: %d\nЗаблокировали бота: %d\nНе начинали чат с ботом: %d\nЗабанены: %d\n" +
+		"Отписались от новостей: %d\nОшибки: %d",
+
+	"rate_limit.too_often": "⏳ Слишком часто! Подождите немного и попробуйте снова.",
+	"rate_limit.repeated":  "⏳ Уже выполняется, подождите",
+	"rate_limit.search":    "⏳ Слишком часто! Поиск можно повторить через минуту.",
+	"rate_limit.busy":      "⏳ Бот не успевает за запросами в этом чате, попробуйте чуть позже",
+}
@@ -1,10 +1,50 @@
 package model
 
 type Movie struct {
-	Id          int
-	Title       string
-	Year        string
-	Rating      string
-	Description string
-	Poster      string
+	Id    int
+	Title string
+	// OriginalTitle is the international or original-language title. It is
+	// empty when the movie only has a Russian title.
+	OriginalTitle string
+	Year          string
+	Rating        string
+	Ratings       Ratings
+	Description   string
+	Poster        string
+	AgeRating     int
+	Character     string
+	IsSeries      bool
+	SeriesLength  int
+	Status        string
+	WatchOptions  []WatchOption
+	Trailers      []Trailer
+	Similar       []Movie
+	Sequels       []Movie
+}
+
+type WatchOption struct {
+	Name string
+	Logo string
+	URL  string
+}
+
+type Trailer struct {
+	Name string
+	URL  string
+	Site string
+}
+
+// Ratings holds scores from every source with their vote counts. Critic
+// ratings from Russian critics and audience anticipation are percentages.
+type Ratings struct {
+	Kp                      float64
+	KpVotes                 int
+	Imdb                    float64
+	ImdbVotes               int
+	FilmCritics             float64
+	FilmCriticsVotes        int
+	RussianFilmCritics      float64
+	RussianFilmCriticsVotes int
+	Await                   float64
+	AwaitVotes              int
 }
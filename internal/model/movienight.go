@@ -0,0 +1,26 @@
+package model
+
+import "time"
+
+const (
+	MovieNightNominating = "nominating"
+	MovieNightVoting     = "voting"
+)
+
+type MovieNight struct {
+	ChatID        int64     `json:"chat_id"`
+	OrganizerID   int64     `json:"organizer_id"`
+	Status        string    `json:"status"`
+	Nominees      []Nominee `json:"nominees"`
+	PollMessageID int       `json:"poll_message_id"`
+	Deadline      time.Time `json:"deadline"`
+	// Lang is the organizer's UI language, used for messages sent when the
+	// vote closes.
+	Lang string `json:"lang"`
+}
+
+type Nominee struct {
+	MovieID int    `json:"movie_id"`
+	Title   string `json:"title"`
+	UserID  int64  `json:"user_id"`
+}
@@ -1,10 +1,17 @@
 package model
 
 type Person struct {
-	Id     int
-	Name   string
-	EnName string
-	Sex    string
-	Photo  string
-	Birth  string
+	Id          int
+	Name        string
+	EnName      string
+	Sex         string
+	Photo       string
+	Birth       string
+	Death       string
+	Age         int
+	BirthPlace  string
+	Professions []string
+	Height      int
+	Facts       []string
+	RoleCounts  map[string]int
 }
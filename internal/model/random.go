@@ -0,0 +1,9 @@
+package model
+
+type RandomFilter struct {
+	Genre            string  `json:"genre"`
+	MinRating        float64 `json:"min_rating"`
+	YearFrom         int     `json:"year_from"`
+	YearTo           int     `json:"year_to"`
+	ExcludeWatchlist bool    `json:"exclude_watchlist"`
+}
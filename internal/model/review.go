@@ -0,0 +1,10 @@
+package model
+
+type Review struct {
+	Id     int
+	Title  string
+	Type   string
+	Text   string
+	Author string
+	Date   string
+}
@@ -0,0 +1,15 @@
+package model
+
+type Season struct {
+	Number        int
+	Name          string
+	EpisodesCount int
+	Episodes      []Episode
+}
+
+type Episode struct {
+	Number      int
+	Name        string
+	AirDate     string
+	Description string
+}
@@ -0,0 +1,18 @@
+package model
+
+// Settings are a user's preferences, edited with /settings.
+type Settings struct {
+	ResultsPerPage int `json:"results_per_page"`
+	// Layout is how result pages are rendered.
+	Layout string `json:"layout"`
+	// Language is the UI locale. Empty means it follows the user's Telegram
+	// language.
+	Language            string `json:"language"`
+	TitleMode           string `json:"title_mode"`
+	HideAdult           bool   `json:"hide_adult"`
+	NotifyAnnouncements bool   `json:"notify_announcements"`
+	NotifyMovieNights   bool   `json:"notify_movie_nights"`
+	// Posters is the poster toggle that Layout replaced. It is only read from
+	// settings saved before layouts existed.
+	Posters *bool `json:"posters,omitempty"`
+}
@@ -1,8 +1,16 @@
 package model
 
 type SearchState struct {
-	Type     string `json:"type"`
-	Query    string `json:"query"`
-	PersonID int    `json:"person_id"`
-	Page     int    `json:"page"`
+	SessionID string `json:"session_id"`
+	UserID    int64  `json:"user_id"`
+	Type      string `json:"type"`
+	Query     string `json:"query"`
+	PersonID  int    `json:"person_id"`
+	Role      string `json:"role"`
+	Sort      string `json:"sort"`
+	MovieID   int    `json:"movie_id"`
+	Page      int    `json:"page"`
+	// Limit is the page size, fixed when the session starts so that page
+	// numbers stay valid if the user changes it later.
+	Limit int `json:"limit"`
 }
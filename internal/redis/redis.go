@@ -3,6 +3,8 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"kinopoisk-bot/internal/model"
 	"log/slog"
 	"strconv"
@@ -32,9 +34,13 @@ func NewRedisClient(addr string, password string, db int, stateTTL time.Duration
 	return &RedisClient{client: client, stateTTL: stateTTL}, nil
 }
 
-func (r *RedisClient) SaveState(chatID int64, state model.SearchState) error {
+func stateKey(chatID, userID int64) string {
+	return fmt.Sprintf("state:%d:%d", chatID, userID)
+}
+
+func (r *RedisClient) SaveState(chatID, userID int64, state model.SearchState) error {
 	ctx := context.Background()
-	key := strconv.FormatInt(chatID, 10)
+	key := stateKey(chatID, userID)
 	data, err := json.Marshal(state)
 	if err != nil {
 		slog.Error("Error marshaling state", "error", err)
@@ -43,9 +49,9 @@ func (r *RedisClient) SaveState(chatID int64, state model.SearchState) error {
 	return r.client.Set(ctx, key, data, r.stateTTL).Err()
 }
 
-func (r *RedisClient) GetState(chatID int64) (*model.SearchState, error) {
+func (r *RedisClient) GetState(chatID, userID int64) (*model.SearchState, error) {
 	ctx := context.Background()
-	key := strconv.FormatInt(chatID, 10)
+	key := stateKey(chatID, userID)
 	data, err := r.client.Get(ctx, key).Bytes()
 	if err != nil {
 		if err == redis.Nil {
@@ -63,8 +69,511 @@ func (r *RedisClient) GetState(chatID int64) (*model.SearchState, error) {
 	return &state, nil
 }
 
-func (r *RedisClient) DeleteState(chatID int64) error {
+func (r *RedisClient) DeleteState(chatID, userID int64) error {
 	ctx := context.Background()
-	key := strconv.FormatInt(chatID, 10)
+	key := stateKey(chatID, userID)
 	return r.client.Del(ctx, key).Err()
 }
+
+func sessionKey(chatID int64, sessionID string) string {
+	return fmt.Sprintf("session:%d:%s", chatID, sessionID)
+}
+
+func (r *RedisClient) SaveSession(chatID int64, state model.SearchState) error {
+	ctx := context.Background()
+	data, err := json.Marshal(state)
+	if err != nil {
+		slog.Error("Error marshaling session", "error", err)
+		return err
+	}
+	return r.client.Set(ctx, sessionKey(chatID, state.SessionID), data, r.stateTTL).Err()
+}
+
+func (r *RedisClient) GetSession(chatID int64, sessionID string) (*model.SearchState, error) {
+	ctx := context.Background()
+	data, err := r.client.Get(ctx, sessionKey(chatID, sessionID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		slog.Error("Error getting session", "error", err)
+		return nil, err
+	}
+
+	var state model.SearchState
+	if err := json.Unmarshal(data, &state); err != nil {
+		slog.Error("Error unmarshaling session", "error", err)
+		return nil, err
+	}
+	return &state, nil
+}
+
+const movieNightDeadlinesKey = "movienight:deadlines"
+
+func movieNightKey(chatID int64) string {
+	return fmt.Sprintf("movienight:%d", chatID)
+}
+
+// movieNightNominatingTTL expires movie nights whose organizer never started
+// the vote. Nights being voted on live until a day after their deadline.
+const movieNightNominatingTTL = 48 * time.Hour
+
+var (
+	// ErrNoMovieNight is returned when updating a chat without a movie night.
+	ErrNoMovieNight = errors.New("no movie night")
+	// ErrMovieNightConflict is returned when a movie night kept changing
+	// under an update.
+	ErrMovieNightConflict = errors.New("movie night changed concurrently")
+)
+
+const movieNightUpdateAttempts = 5
+
+func movieNightTTL(night model.MovieNight) time.Duration {
+	if night.Status == model.MovieNightVoting && !night.Deadline.IsZero() {
+		return time.Until(night.Deadline) + 24*time.Hour
+	}
+	return movieNightNominatingTTL
+}
+
+func (r *RedisClient) SaveMovieNight(night model.MovieNight) error {
+	ctx := context.Background()
+	data, err := json.Marshal(night)
+	if err != nil {
+		slog.Error("Error marshaling movie night", "error", err)
+		return err
+	}
+	return r.client.Set(ctx, movieNightKey(night.ChatID), data, movieNightTTL(night)).Err()
+}
+
+func (r *RedisClient) GetMovieNight(chatID int64) (*model.MovieNight, error) {
+	ctx := context.Background()
+	return getMovieNight(ctx, r.client, chatID)
+}
+
+func getMovieNight(ctx context.Context, cmd redis.Cmdable, chatID int64) (*model.MovieNight, error) {
+	data, err := cmd.Get(ctx, movieNightKey(chatID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return nil, nil
+		}
+		slog.Error("Error getting movie night", "error", err)
+		return nil, err
+	}
+
+	var night model.MovieNight
+	if err := json.Unmarshal(data, &night); err != nil {
+		slog.Error("Error unmarshaling movie night", "error", err)
+		return nil, err
+	}
+	return &night, nil
+}
+
+// UpdateMovieNight atomically applies update to the chat's movie night and
+// returns the saved night. update may return an error to leave the night
+// unchanged, and is called again if the night changes concurrently.
+func (r *RedisClient) UpdateMovieNight(chatID int64, update func(night *model.MovieNight) error) (*model.MovieNight, error) {
+	ctx := context.Background()
+	key := movieNightKey(chatID)
+	var saved *model.MovieNight
+
+	for attempt := 0; attempt < movieNightUpdateAttempts; attempt++ {
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			night, err := getMovieNight(ctx, tx, chatID)
+			if err != nil {
+				return err
+			}
+			if night == nil {
+				return ErrNoMovieNight
+			}
+			if err := update(night); err != nil {
+				return err
+			}
+			data, err := json.Marshal(night)
+			if err != nil {
+				return err
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Set(ctx, key, data, movieNightTTL(*night))
+				return nil
+			})
+			saved = night
+			return err
+		}, key)
+		if err != redis.TxFailedErr {
+			return saved, err
+		}
+	}
+	return nil, ErrMovieNightConflict
+}
+
+// TakeMovieNight atomically deletes the chat's movie night and returns it if
+// it is still voting in the poll pollMessageID, so that only one caller gets
+// to finish it. It returns nil when the night has been finished or cancelled
+// meanwhile.
+func (r *RedisClient) TakeMovieNight(chatID int64, pollMessageID int) (*model.MovieNight, error) {
+	ctx := context.Background()
+	key := movieNightKey(chatID)
+	var taken *model.MovieNight
+
+	for attempt := 0; attempt < movieNightUpdateAttempts; attempt++ {
+		err := r.client.Watch(ctx, func(tx *redis.Tx) error {
+			night, err := getMovieNight(ctx, tx, chatID)
+			if err != nil {
+				return err
+			}
+			if night == nil || night.Status != model.MovieNightVoting || night.PollMessageID != pollMessageID {
+				taken = nil
+				return nil
+			}
+			_, err = tx.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+				pipe.Del(ctx, key)
+				pipe.ZRem(ctx, movieNightDeadlinesKey, strconv.FormatInt(chatID, 10))
+				return nil
+			})
+			taken = night
+			return err
+		}, key)
+		if err != redis.TxFailedErr {
+			return taken, err
+		}
+	}
+	return nil, ErrMovieNightConflict
+}
+
+func (r *RedisClient) DeleteMovieNight(chatID int64) error {
+	ctx := context.Background()
+	pipe := r.client.TxPipeline()
+	pipe.Del(ctx, movieNightKey(chatID))
+	pipe.ZRem(ctx, movieNightDeadlinesKey, strconv.FormatInt(chatID, 10))
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// ScheduleMovieNight registers the voting deadline of a chat's movie night.
+func (r *RedisClient) ScheduleMovieNight(chatID int64, deadline time.Time) error {
+	ctx := context.Background()
+	return r.client.ZAdd(ctx, movieNightDeadlinesKey, &redis.Z{
+		Score:  float64(deadline.Unix()),
+		Member: strconv.FormatInt(chatID, 10),
+	}).Err()
+}
+
+// DueMovieNights returns chats whose movie night voting deadline has passed.
+func (r *RedisClient) DueMovieNights(now time.Time) ([]int64, error) {
+	ctx := context.Background()
+	members, err := r.client.ZRangeByScore(ctx, movieNightDeadlinesKey, &redis.ZRangeBy{
+		Min: "-inf",
+		Max: strconv.FormatInt(now.Unix(), 10),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	chatIDs := make([]int64, 0, len(members))
+	for _, member := range members {
+		chatID, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			slog.Warn("Invalid movie night deadline member", "member", member)
+			continue
+		}
+		chatIDs = append(chatIDs, chatID)
+	}
+	return chatIDs, nil
+}
+
+func randomFilterKey(userID int64) string {
+	return fmt.Sprintf("random_filter:%d", userID)
+}
+
+func watchlistKey(userID int64) string {
+	return fmt.Sprintf("watchlist:%d", userID)
+}
+
+func (r *RedisClient) SaveRandomFilter(userID int64, filter model.RandomFilter) error {
+	ctx := context.Background()
+	data, err := json.Marshal(filter)
+	if err != nil {
+		slog.Error("Error marshaling random filter", "error", err)
+		return err
+	}
+	return r.client.Set(ctx, randomFilterKey(userID), data, 0).Err()
+}
+
+// GetRandomFilter returns the user's random picker constraints, or an empty
+// filter when none were set.
+func (r *RedisClient) GetRandomFilter(userID int64) (model.RandomFilter, error) {
+	ctx := context.Background()
+	var filter model.RandomFilter
+	data, err := r.client.Get(ctx, randomFilterKey(userID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return filter, nil
+		}
+		slog.Error("Error getting random filter", "error", err)
+		return filter, err
+	}
+
+	if err := json.Unmarshal(data, &filter); err != nil {
+		slog.Error("Error unmarshaling random filter", "error", err)
+		return filter, err
+	}
+	return filter, nil
+}
+
+func (r *RedisClient) AddToWatchlist(userID int64, movieID int) error {
+	ctx := context.Background()
+	return r.client.SAdd(ctx, watchlistKey(userID), movieID).Err()
+}
+
+func (r *RedisClient) IsInWatchlist(userID int64, movieID int) (bool, error) {
+	ctx := context.Background()
+	return r.client.SIsMember(ctx, watchlistKey(userID), movieID).Result()
+}
+
+func watchServicesKey(userID int64) string {
+	return fmt.Sprintf("watch_services:%d", userID)
+}
+
+// ToggleWatchService adds the service to the user's subscriptions or removes
+// it if it is already there.
+func (r *RedisClient) ToggleWatchService(userID int64, service string) error {
+	ctx := context.Background()
+	key := watchServicesKey(userID)
+	removed, err := r.client.SRem(ctx, key, service).Result()
+	if err != nil {
+		return err
+	}
+	if removed > 0 {
+		return nil
+	}
+	return r.client.SAdd(ctx, key, service).Err()
+}
+
+func (r *RedisClient) GetWatchServices(userID int64) ([]string, error) {
+	ctx := context.Background()
+	return r.client.SMembers(ctx, watchServicesKey(userID)).Result()
+}
+
+func settingsKey(userID int64) string {
+	return fmt.Sprintf("settings:%d", userID)
+}
+
+func (r *RedisClient) SaveSettings(userID int64, settings model.Settings) error {
+	ctx := context.Background()
+	data, err := json.Marshal(settings)
+	if err != nil {
+		slog.Error("Error marshaling settings", "error", err)
+		return err
+	}
+	return r.client.Set(ctx, settingsKey(userID), data, 0).Err()
+}
+
+// languageKey and titleModeKey held the language and title preferences
+// before they moved into settings.
+func languageKey(userID int64) string {
+	return fmt.Sprintf("language:%d", userID)
+}
+
+func titleModeKey(userID int64) string {
+	return fmt.Sprintf("title_mode:%d", userID)
+}
+
+// GetSettings returns the user's settings. Fields missing from the stored
+// value, including settings added after it was saved, keep their values from
+// defaults. Users who haven't saved settings yet get the language and title
+// preferences they chose before settings existed.
+func (r *RedisClient) GetSettings(userID int64, defaults model.Settings) (model.Settings, error) {
+	ctx := context.Background()
+	settings := defaults
+	data, err := r.client.Get(ctx, settingsKey(userID)).Bytes()
+	if err != nil {
+		if err == redis.Nil {
+			return r.getLegacySettings(ctx, userID, settings)
+		}
+		slog.Error("Error getting settings", "error", err)
+		return defaults, err
+	}
+
+	if err := json.Unmarshal(data, &settings); err != nil {
+		slog.Error("Error unmarshaling settings", "error", err)
+		return defaults, err
+	}
+	return settings, nil
+}
+
+func (r *RedisClient) getLegacySettings(ctx context.Context, userID int64, settings model.Settings) (model.Settings, error) {
+	values, err := r.client.MGet(ctx, languageKey(userID), titleModeKey(userID)).Result()
+	if err != nil {
+		slog.Error("Error getting legacy settings", "error", err)
+		return settings, err
+	}
+	if language, ok := values[0].(string); ok && language != "" {
+		settings.Language = language
+	}
+	if titleMode, ok := values[1].(string); ok && titleMode != "" {
+		settings.TitleMode = titleMode
+	}
+	return settings, nil
+}
+
+const (
+	bannedKey        = "banned"
+	maintenanceKey   = "maintenance"
+	usersKey         = "users"
+	inactiveUsersKey = "users:inactive"
+	statsSearchKey   = "stats:searches"
+)
+
+func (r *RedisClient) Ban(userID int64) error {
+	ctx := context.Background()
+	return r.client.SAdd(ctx, bannedKey, userID).Err()
+}
+
+func (r *RedisClient) Unban(userID int64) error {
+	ctx := context.Background()
+	return r.client.SRem(ctx, bannedKey, userID).Err()
+}
+
+func (r *RedisClient) IsBanned(userID int64) (bool, error) {
+	ctx := context.Background()
+	return r.client.SIsMember(ctx, bannedKey, userID).Result()
+}
+
+func (r *RedisClient) CountBanned() (int64, error) {
+	ctx := context.Background()
+	return r.client.SCard(ctx, bannedKey).Result()
+}
+
+func (r *RedisClient) SetMaintenance(enabled bool) error {
+	ctx := context.Background()
+	if !enabled {
+		return r.client.Del(ctx, maintenanceKey).Err()
+	}
+	return r.client.Set(ctx, maintenanceKey, 1, 0).Err()
+}
+
+func (r *RedisClient) IsMaintenance() (bool, error) {
+	ctx := context.Background()
+	count, err := r.client.Exists(ctx, maintenanceKey).Result()
+	return count > 0, err
+}
+
+// RegisterUser adds the user to the registry of everyone who has used the
+// bot. A user who comes back is active again.
+func (r *RedisClient) RegisterUser(userID int64) error {
+	ctx := context.Background()
+	pipe := r.client.TxPipeline()
+	pipe.SAdd(ctx, usersKey, userID)
+	pipe.SRem(ctx, inactiveUsersKey, userID)
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+// MarkUserInactive excludes the user from broadcasts until they write again,
+// e.g. after they blocked the bot.
+func (r *RedisClient) MarkUserInactive(userID int64) error {
+	ctx := context.Background()
+	return r.client.SAdd(ctx, inactiveUsersKey, userID).Err()
+}
+
+func (r *RedisClient) GetActiveUsers() ([]int64, error) {
+	ctx := context.Background()
+	members, err := r.client.SDiff(ctx, usersKey, inactiveUsersKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	users := make([]int64, 0, len(members))
+	for _, member := range members {
+		userID, err := strconv.ParseInt(member, 10, 64)
+		if err != nil {
+			continue
+		}
+		users = append(users, userID)
+	}
+	return users, nil
+}
+
+// CountUsers returns the number of registered users and how many of them are
+// inactive.
+func (r *RedisClient) CountUsers() (total int64, inactive int64, err error) {
+	ctx := context.Background()
+	if total, err = r.client.SCard(ctx, usersKey).Result(); err != nil {
+		return 0, 0, err
+	}
+	inactive, err = r.client.SCard(ctx, inactiveUsersKey).Result()
+	return total, inactive, err
+}
+
+func broadcastKey(id string) string {
+	return fmt.Sprintf("broadcast:%s", id)
+}
+
+// SaveBroadcast keeps a broadcast draft until it is confirmed or expires.
+func (r *RedisClient) SaveBroadcast(id string, text string) error {
+	ctx := context.Background()
+	return r.client.Set(ctx, broadcastKey(id), text, time.Hour).Err()
+}
+
+// TakeBroadcast returns the draft and deletes it, so that it can only be sent
+// once. The text is empty when the draft doesn't exist.
+func (r *RedisClient) TakeBroadcast(id string) (string, error) {
+	ctx := context.Background()
+	text, err := r.client.GetDel(ctx, broadcastKey(id)).Result()
+	if err == redis.Nil {
+		return "", nil
+	}
+	return text, err
+}
+
+// IncrSearches counts a search of the given type.
+func (r *RedisClient) IncrSearches(searchType string) error {
+	ctx := context.Background()
+	return r.client.HIncrBy(ctx, statsSearchKey, searchType, 1).Err()
+}
+
+func (r *RedisClient) GetSearchStats() (map[string]int64, error) {
+	ctx := context.Background()
+	values, err := r.client.HGetAll(ctx, statsSearchKey).Result()
+	if err != nil {
+		return nil, err
+	}
+	stats := make(map[string]int64, len(values))
+	for searchType, value := range values {
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			continue
+		}
+		stats[searchType] = count
+	}
+	return stats, nil
+}
+
+func rateLimitKey(action string, userID int64) string {
+	return fmt.Sprintf("ratelimit:%s:%d", action, userID)
+}
+
+// AllowRate counts an action of the user in a fixed window and reports
+// whether it is within limit.
+func (r *RedisClient) AllowRate(action string, userID int64, limit int, window time.Duration) (bool, error) {
+	ctx := context.Background()
+	key := rateLimitKey(action, userID)
+	pipe := r.client.TxPipeline()
+	// Окно отсчитывается от первого действия: SETNX задает TTL только новому ключу, а INCR его сохраняет
+	pipe.SetNX(ctx, key, 0, window)
+	count := pipe.Incr(ctx, key)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return true, err
+	}
+	return count.Val() <= int64(limit), nil
+}
+
+func debounceKey(userID int64, data string) string {
+	return fmt.Sprintf("debounce:%d:%s", userID, data)
+}
+
+// Debounce reports whether the user's action with this data is the first one
+// within interval.
+func (r *RedisClient) Debounce(userID int64, data string, interval time.Duration) (bool, error) {
+	ctx := context.Background()
+	return r.client.SetNX(ctx, debounceKey(userID, data), 1, interval).Result()
+}